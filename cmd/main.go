@@ -8,6 +8,8 @@ import (
 	"kumparan-test/internal/api"
 	"kumparan-test/internal/article"
 	"kumparan-test/internal/author"
+	"kumparan-test/internal/notify"
+	"kumparan-test/internal/outbox"
 	"kumparan-test/pkg/database"
 	"kumparan-test/pkg/search"
 	"net/http"
@@ -25,6 +27,7 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/labstack/gommon/log"
+	"github.com/olivere/elastic/v7"
 	"github.com/sirupsen/logrus"
 )
 
@@ -69,6 +72,11 @@ func main() {
 	// Default configuration file is empty string, OS ENV variable will be used if config file empty or not found
 	configPath := flag.String("config", "", "config file path")
 	migrateDB := flag.Bool("migrate", false, "run database migrations and exit")
+	reindex := flag.Bool("reindex", false, "rebuild the Elasticsearch articles index from PostgreSQL and exit")
+	reindexBatchSize := flag.Int("reindex-batch-size", 500, "number of articles per batch when reindexing or reconciling")
+	reindexDeleteOld := flag.Bool("reindex-delete-old", false, "delete the previous articles index after a successful reindex")
+	reconcile := flag.Bool("reconcile", false, "repair drift between PostgreSQL and the Elasticsearch articles index and exit")
+	reloadSynonyms := flag.Bool("reload-synonyms", false, "apply edits of the Elasticsearch synonyms file to the search indices and exit")
 
 	flag.Parse()
 
@@ -104,25 +112,137 @@ func main() {
 		if err := runMigrations(buildPostgresDSN(&serviceConfig.SourceData)); err != nil {
 			logrus.Fatalf("Database migration failed: %v", err)
 		}
+		// Migrations can only approximate author.NameKey in SQL, so the keys they stored are recomputed here
+		repaired, err := author.RepairNameKeys(context.Background(), author.NewPostgresRepository(dbPool))
+		if err != nil {
+			logrus.Fatalf("Repairing author name keys failed: %v", err)
+		}
+		logrus.Infof("Repaired the name keys of %d authors", repaired)
 		logrus.Info("Database migrations completed successfully. Exiting.")
 		os.Exit(0)
 	}
 
-	// Initialize Elasticsearch
-	esClient, err := search.NewElasticsearchClient(serviceConfig.SourceData.ElasticURL)
-	if err != nil {
-		logrus.Fatalf("Failed to connect to Elasticsearch: %v", err)
+	// Initialize the search backend
+	searchBackend := serviceConfig.ServiceData.SearchBackend
+	if searchBackend == "" {
+		searchBackend = article.BackendElasticsearch
+	}
+	var esClient *elastic.Client
+	var searchService search.SearchService
+	switch searchBackend {
+	case article.BackendElasticsearch:
+		esClient, err = search.NewElasticsearchClient(serviceConfig.SourceData.ElasticURL)
+		if err != nil {
+			logrus.Fatalf("Failed to create Elasticsearch client: %v", err)
+		}
+		defer esClient.Stop()
+		searchService = search.NewCircuitBreaker(search.NewSearchService(esClient), search.BreakerConfig{})
+	case article.BackendMemory:
+		logrus.Warn("Using the in-memory search backend, the index is rebuilt from PostgreSQL on every start")
+		searchService = search.NewMemorySearchService()
+	default:
+		logrus.Fatalf("Unknown search backend '%s', expected elasticsearch or memory", searchBackend)
 	}
-	defer esClient.Stop()
 
 	// Initialize Repositories, Services, and Handlers
-	searchService := search.NewSearchService(esClient)
 	authorRepo := author.NewPostgresRepository(dbPool)
 	authorService := author.NewAuthorService(authorRepo)
 
 	articleRepo := article.NewPostgresRepository(dbPool)
-	articleService := article.NewArticleService(articleRepo, authorService, searchService)
-	apiHandler := api.NewHandler(articleService)
+
+	if (*reindex || *reconcile || *reloadSynonyms) && esClient == nil {
+		logrus.Fatalf("Reindexing, reconciling and reloading synonyms only apply to the elasticsearch search backend")
+	}
+
+	if *reloadSynonyms {
+		logrus.Info("Reloading search synonyms...")
+		if err := search.ReloadSearchAnalyzers(context.Background(), esClient); err != nil {
+			logrus.Fatalf("Reloading search synonyms failed: %v", err)
+		}
+		logrus.Info("Search synonyms reloaded successfully. Exiting.")
+		os.Exit(0)
+	}
+
+	if *reindex {
+		logrus.Info("Reindexing articles...")
+		indexName, err := article.NewReindexer(articleRepo, searchService, *reindexBatchSize).Run(context.Background(), *reindexDeleteOld)
+		if err != nil {
+			logrus.Fatalf("Reindex failed: %v", err)
+		}
+		logrus.Infof("Reindex completed successfully, '%s' now serves '%s'. Exiting.", indexName, search.ArticleIndexName)
+		os.Exit(0)
+	}
+
+	if *reconcile {
+		logrus.Info("Reconciling search index...")
+		if _, err := article.NewReconciler(articleRepo, searchService, *reindexBatchSize).Run(context.Background()); err != nil {
+			logrus.Fatalf("Reconciliation failed: %v", err)
+		}
+		logrus.Info("Reconciliation completed successfully. Exiting.")
+		os.Exit(0)
+	}
+
+	articleService := article.NewArticleService(articleRepo, authorService, searchService, article.SearchConfig{
+		HighlightFragmentSize: serviceConfig.ServiceData.SearchHighlightFragmentSize,
+		HighlightFragments:    serviceConfig.ServiceData.SearchHighlightFragments,
+		HighlightPreTag:       serviceConfig.ServiceData.SearchHighlightPreTag,
+		HighlightPostTag:      serviceConfig.ServiceData.SearchHighlightPostTag,
+		SuggestTimeout:        time.Duration(serviceConfig.ServiceData.SearchSuggestTimeout) * time.Millisecond,
+		Backend:               searchBackend,
+	})
+	savedSearchRepo := article.NewSavedSearchRepository(dbPool)
+	savedSearchService := article.NewSavedSearchService(savedSearchRepo)
+	apiHandler := api.NewHandler(articleService, savedSearchService, authorService)
+
+	alertNotifier, err := notify.New(serviceConfig.ServiceData.AlertNotifier, serviceConfig.ServiceData.AlertWebhookURL)
+	if err != nil {
+		logrus.Fatalf("Invalid alert notifier configuration: %v", err)
+	}
+
+	// Deliver article and saved search changes to the search index through the transactional outbox
+	outboxRepo := outbox.NewPostgresRepository(dbPool)
+	indexer := article.NewIndexer(articleRepo, savedSearchRepo, searchService, alertNotifier)
+	outboxWorker := outbox.NewWorker(outboxRepo, indexer, outbox.WorkerConfig{
+		PollInterval: time.Duration(serviceConfig.ServiceData.OutboxPollInterval) * time.Second,
+		BatchSize:    serviceConfig.ServiceData.OutboxBatchSize,
+		MaxAttempts:  serviceConfig.ServiceData.OutboxMaxAttempts,
+	})
+
+	// The in-memory backend starts empty, so it is filled before serving any search
+	if esClient == nil {
+		if err := fillMemoryIndex(context.Background(), articleRepo, savedSearchRepo, searchService, *reindexBatchSize); err != nil {
+			logrus.Fatalf("Failed to fill the in-memory search index: %v", err)
+		}
+	}
+
+	// Searches fall back to PostgreSQL until Elasticsearch is reachable. Indexing, and the
+	// periodic reconciliation that repairs whatever the outbox could not deliver, wait for the index.
+	indexCtx, stopIndex := context.WithCancel(context.Background())
+	defer stopIndex()
+	go func() {
+		registerSavedSearches := func(ctx context.Context, index string) error {
+			return article.RegisterSavedSearches(ctx, savedSearchRepo, searchService, index)
+		}
+		migrated := false
+		if esClient != nil {
+			var ready bool
+			if migrated, ready = waitForSearchIndex(indexCtx, esClient, serviceConfig.SourceData.ElasticURL, registerSavedSearches); !ready {
+				return
+			}
+		}
+		outboxWorker.Start()
+		reconciler := article.NewReconciler(articleRepo, searchService, 0)
+		// Documents copied from an older index miss the fields added since, such as the author name keys
+		// that filters match on, so they are repaired right away rather than at the next interval
+		if migrated {
+			if _, err := reconciler.Run(indexCtx); err != nil && indexCtx.Err() == nil {
+				logrus.WithError(err).Error("Search index reconciliation after migrating the index failed")
+			}
+		}
+		if interval := serviceConfig.ServiceData.ReconcileInterval; interval > 0 {
+			reconciler.RunEvery(indexCtx, time.Duration(interval)*time.Minute)
+		}
+	}()
 
 	// Echo instance
 	e := echo.New()
@@ -168,9 +288,63 @@ func main() {
 		logrus.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	stopIndex()
+
+	// Stop accepting new work first, then flush what the last requests queued
+	if err := outboxWorker.Stop(ctx); err != nil {
+		logrus.WithError(err).Warn("Outbox worker did not drain before shutdown timeout")
+	}
+
 	logrus.Info("Server exited gracefully")
 }
 
+// waitForSearchIndex retries until Elasticsearch is reachable and the articles and saved searches
+// indices are ready with the current synonyms, returning false if ctx is cancelled first. registerSavedSearches fills a new
+// saved searches index. It also tells whether the articles index was migrated from an older mapping.
+func waitForSearchIndex(ctx context.Context, esClient *elastic.Client, url string, registerSavedSearches func(ctx context.Context, index string) error) (migrated bool, ready bool) {
+	for {
+		pingCtx, cancelPing := context.WithTimeout(ctx, 10*time.Second)
+		err := search.Ping(pingCtx, esClient, url)
+		cancelPing()
+		if err == nil {
+			// Migrating an older index copies every document, so allow it more time than a request
+			indexCtx, cancelIndex := context.WithTimeout(ctx, 10*time.Minute)
+			var copied bool
+			copied, err = search.EnsureArticleIndex(indexCtx, esClient)
+			migrated = migrated || copied
+			if err == nil {
+				err = search.EnsureSavedSearchIndex(indexCtx, esClient, registerSavedSearches)
+			}
+			// Picks up synonyms edited on the Elasticsearch nodes since the indices were last loaded
+			if err == nil {
+				err = search.ReloadSearchAnalyzers(indexCtx, esClient)
+			}
+			cancelIndex()
+			if err == nil {
+				return migrated, true
+			}
+		}
+		logrus.WithError(err).Warn("Elasticsearch not ready, searching with PostgreSQL and retrying in 10s")
+
+		select {
+		case <-ctx.Done():
+			return migrated, false
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// fillMemoryIndex indexes every article and saved search of PostgreSQL in the in-memory search backend.
+func fillMemoryIndex(ctx context.Context, articleRepo article.Repository, savedSearchRepo article.SavedSearchRepository, searchService search.SearchService, batchSize int) error {
+	if _, err := article.NewReindexer(articleRepo, searchService, batchSize).Run(ctx, true); err != nil {
+		return err
+	}
+	if err := searchService.CreateIndex(ctx, search.SavedSearchIndexName, search.SavedSearchMapping); err != nil {
+		return err
+	}
+	return article.RegisterSavedSearches(ctx, savedSearchRepo, searchService, search.SavedSearchIndexName)
+}
+
 // runMigrations applies database migrations using golang-migrate.
 func runMigrations(dsn string) error {
 	m, err := migrate.New(
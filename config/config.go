@@ -49,6 +49,30 @@ type ServiceDataConfig struct {
 	Address   string `yaml:"address" env:"SERVICE_DATA_PORT"`
 	LogLevel  string `yaml:"log_level" env:"SERVICE_DATA_LOG_LEVEL"`
 	RateLimit int    `yaml:"rate_limit" env:"SERVICE_DATA_RATE_LIMIT"`
+
+	// Outbox worker settings, zero values fall back to the worker defaults
+	OutboxPollInterval int `yaml:"outbox_poll_interval" env:"SERVICE_DATA_OUTBOX_POLL_INTERVAL"` // seconds
+	OutboxBatchSize    int `yaml:"outbox_batch_size" env:"SERVICE_DATA_OUTBOX_BATCH_SIZE"`
+	OutboxMaxAttempts  int `yaml:"outbox_max_attempts" env:"SERVICE_DATA_OUTBOX_MAX_ATTEMPTS"`
+
+	// Search backend: elasticsearch (default) or memory, an in-process index for local development
+	SearchBackend string `yaml:"search_backend" env:"SERVICE_DATA_SEARCH_BACKEND"`
+
+	// Search index reconciliation, disabled when the interval is zero
+	ReconcileInterval int `yaml:"reconcile_interval" env:"SERVICE_DATA_RECONCILE_INTERVAL"` // minutes
+
+	// Search result highlighting, empty values fall back to the service defaults
+	SearchHighlightFragmentSize int    `yaml:"search_highlight_fragment_size" env:"SERVICE_DATA_SEARCH_HIGHLIGHT_FRAGMENT_SIZE"` // characters
+	SearchHighlightFragments    int    `yaml:"search_highlight_fragments" env:"SERVICE_DATA_SEARCH_HIGHLIGHT_FRAGMENTS"`         // per field
+	SearchHighlightPreTag       string `yaml:"search_highlight_pre_tag" env:"SERVICE_DATA_SEARCH_HIGHLIGHT_PRE_TAG"`
+	SearchHighlightPostTag      string `yaml:"search_highlight_post_tag" env:"SERVICE_DATA_SEARCH_HIGHLIGHT_POST_TAG"`
+
+	// Autocomplete time budget, zero falls back to the service default
+	SearchSuggestTimeout int `yaml:"search_suggest_timeout" env:"SERVICE_DATA_SEARCH_SUGGEST_TIMEOUT"` // milliseconds
+
+	// Delivery of saved search alerts: log (default) or webhook
+	AlertNotifier   string `yaml:"alert_notifier" env:"SERVICE_DATA_ALERT_NOTIFIER"`
+	AlertWebhookURL string `yaml:"alert_webhook_url" env:"SERVICE_DATA_ALERT_WEBHOOK_URL"`
 }
 
 // SourceDataConfig contains the source data configuration.
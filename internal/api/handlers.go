@@ -1,10 +1,18 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"expvar"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"kumparan-test/internal/article"
+	"kumparan-test/internal/author"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -12,15 +20,27 @@ import (
 
 const (
 	CustomIDHeaderKeys = "Custom-ID"
+	// SearchBackendHeaderKey names the backend that answered an article listing
+	SearchBackendHeaderKey = "X-Search-Backend"
 )
 
+// publicMetrics are the expvar variables served on /metrics. The others, such as the command line
+// (which can carry the database DSN) and memstats, are left out.
+var publicMetrics = []string{"article_reconciliation"}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 type Handler struct {
-	articleService article.Service
+	articleService     article.Service
+	savedSearchService article.SavedSearchService
+	authorService      author.Service
 }
 
-func NewHandler(articleSvc article.Service) *Handler {
+func NewHandler(articleSvc article.Service, savedSearchSvc article.SavedSearchService, authorSvc author.Service) *Handler {
 	return &Handler{
-		articleService: articleSvc,
+		articleService:     articleSvc,
+		savedSearchService: savedSearchSvc,
+		authorService:      authorSvc,
 	}
 }
 
@@ -29,23 +49,45 @@ func (h *Handler) RegisterRoutes(e *echo.Echo) {
 	e.Add("GET", "/healthcheck", func(c echo.Context) error {
 		return c.String(http.StatusOK, "I'm alive")
 	})
+	e.GET("/metrics", h.GetMetrics)
 
 	v1 := e.Group("/api/v1")
 
 	articles := v1.Group("/articles")
 	articles.POST("", h.PostArticle)
 	articles.GET("", h.GetArticles)
+	articles.GET("/suggest", h.SuggestArticles)
+	articles.GET("/:id", h.GetArticleByID)
+	articles.GET("/:id/related", h.GetRelatedArticles)
+	articles.PUT("/:id", h.UpdateArticle)
+	articles.PATCH("/:id", h.PatchArticle)
+	articles.DELETE("/:id", h.DeleteArticle)
+
+	savedSearches := v1.Group("/saved-searches")
+	savedSearches.POST("", h.CreateSavedSearch)
+	savedSearches.GET("", h.GetSavedSearches)
+	savedSearches.GET("/:id", h.GetSavedSearchByID)
+	savedSearches.DELETE("/:id", h.DeleteSavedSearch)
+
+	authors := v1.Group("/authors")
+	authors.POST("", h.CreateAuthor)
+	authors.GET("", h.GetAuthors)
+	authors.GET("/:id", h.GetAuthorByID)
+	authors.PUT("/:id", h.UpdateAuthor)
+	authors.DELETE("/:id", h.DeleteAuthor)
+	authors.POST("/:id/merge", h.MergeAuthors)
+	authors.GET("/:slug/articles", h.GetAuthorArticles)
 }
 
 // PostArticle handles the creation of a new article.
 // @Summary Post a new article
-// @Description Creates a new news article with a title, body, and author.
+// @Description Creates a new news article with a title, body, and either an author or a list of credited authors with their roles.
 // @Tags articles
 // @Accept json
 // @Produce json
 // @Param article body article.CreateArticleRequest true "Article object to be created"
 // @Success 201 {object} article.Article "Successfully created article"
-// @Failure 400 {object} ErrorResponse "Invalid request payload or missing fields"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, missing fields or invalid authors"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /articles [post]
 func (h *Handler) PostArticle(e echo.Context) error {
@@ -55,12 +97,15 @@ func (h *Handler) PostArticle(e echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload or malformed JSON")
 	}
 
-	if req.Title == "" || req.Body == "" || req.Author == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "Missing required fields: title, body, and author are mandatory")
+	if req.Title == "" || req.Body == "" || (req.Author == "" && len(req.Authors) == 0) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required fields: title, body, and author or authors are mandatory")
 	}
 
 	createdArticle, err := h.articleService.PostArticle(e.Request().Context(), &req)
 	if err != nil {
+		if errors.Is(err, article.ErrInvalidAuthors) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create article due to internal error")
 	}
 
@@ -69,39 +114,752 @@ func (h *Handler) PostArticle(e echo.Context) error {
 
 // GetArticles handles retrieving a list of articles.
 // @Summary Get a list of articles
-// @Description Retrieves a list of news articles, sorted by latest first, with optional filters.
+// @Description Retrieves a list of news articles with optional filters. Searches are sorted by relevance and listings by latest first unless sort is given.
 // @Tags articles
 // @Accept json
 // @Produce json
-// @Param query query string false "Keywords to search in article title and body"
-// @Param author query string false "Filter by author's name"
+// @Param query query string false "Keywords to search in article title and body. Supports quoted phrases, -exclusions and author:, before:, after: (YYYY-MM-DD) operators"
+// @Param author query []string false "Filter by the name of any credited author, repeat for any of several authors" collectionFormat(multi)
+// @Param author_id query []string false "Filter by the ID (UUID) of any credited author, repeat for any of several authors" collectionFormat(multi)
+// @Param from query string false "Only articles created at or after this date (YYYY-MM-DD) or time (RFC 3339)"
+// @Param to query string false "Only articles created up to this date, inclusive, or before this time (RFC 3339)"
 // @Param page query int false "Page number for pagination (default 1)"
 // @Param limit query int false "Number of articles per page (default 10, max 100)"
-// @Success 200 {array} article.Article "Successfully retrieved list of articles"
-// @Failure 400 {object} ErrorResponse "Invalid query parameters"
+// @Param cursor query string false "Opaque cursor from a previous next_cursor, replaces page for keyset pagination"
+// @Param sort query string false "Result order: relevance, newest or oldest"
+// @Param facets query string false "Comma-separated facets to compute on a search: author, date"
+// @Param facet_interval query string false "Bucket size of the date facet: day, week or month (default month)"
+// @Success 200 {object} article.ArticleList "Successfully retrieved page of articles"
+// @Header 200 {string} X-Search-Backend "Backend that answered: elasticsearch, memory or postgres"
+// @Failure 400 {object} ErrorResponse "Invalid query parameters or malformed search query"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Search cursor cannot be continued while Elasticsearch is unavailable"
 // @Router /articles [get]
 func (h *Handler) GetArticles(e echo.Context) error {
+	params := e.QueryParams()
 	filter := &article.ArticleFilter{
 		Query:  e.QueryParam("query"),
-		Author: e.QueryParam("author"),
 		Page:   parseIntOrDefault(e.Request().URL.Query().Get("page"), 1),
 		Limit:  parseIntOrDefault(e.Request().URL.Query().Get("limit"), 10),
+		Cursor: e.QueryParam("cursor"),
+		Sort:   e.QueryParam("sort"),
+
+		Authors:   nonEmpty(params["author"]),
+		AuthorIDs: nonEmpty(params["author_id"]),
+
+		FacetInterval: e.QueryParam("facet_interval"),
+	}
+	if facets := e.QueryParam("facets"); facets != "" {
+		filter.Facets = strings.Split(facets, ",")
+	}
+	for _, id := range filter.AuthorIDs {
+		if !uuidPattern.MatchString(id) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid author_id, expected a UUID")
+		}
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(e.QueryParam("from"), false); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid from, expected YYYY-MM-DD or an RFC 3339 time")
+	}
+	if filter.To, err = parseTimeParam(e.QueryParam("to"), true); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid to, expected YYYY-MM-DD or an RFC 3339 time")
+	}
+
+	articles, err := h.articleService.GetArticles(e.Request().Context(), filter)
+	if err != nil {
+		if errors.Is(err, article.ErrInvalidCursor) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid cursor")
+		}
+		if errors.Is(err, article.ErrInvalidSort) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid sort, expected one of: relevance, newest, oldest")
+		}
+		if errors.Is(err, article.ErrInvalidFacet) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid facets, expected author and/or date with facet_interval day, week or month")
+		}
+		if errors.Is(err, article.ErrInvalidQuery) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, article.ErrSearchUnavailable) {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "Search is temporarily unavailable, retry without cursor")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve articles due to internal error")
+	}
+	articles.Links = buildPageLinks(e.Request().URL, articles)
+	if articles.Backend != "" {
+		e.Response().Header().Set(SearchBackendHeaderKey, articles.Backend)
+	}
+
+	return e.JSON(http.StatusOK, articles)
+}
+
+// SuggestArticles handles autocomplete while a search is being typed.
+// @Summary Suggest articles and authors
+// @Description Returns article titles and author names matching the typed prefix. Suggestions are kept small and fast, and are not available while Elasticsearch is down.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param q query string true "Partially typed search"
+// @Param limit query int false "Maximum suggestions of each kind (default 5, max 10)"
+// @Success 200 {object} article.Suggestions "Matching titles and authors"
+// @Failure 400 {object} ErrorResponse "Missing q"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Suggestions are temporarily unavailable"
+// @Router /articles/suggest [get]
+func (h *Handler) SuggestArticles(e echo.Context) error {
+	prefix := strings.TrimSpace(e.QueryParam("q"))
+	if prefix == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required parameter: q")
+	}
+
+	suggestions, err := h.articleService.SuggestArticles(e.Request().Context(), prefix, parseIntOrDefault(e.QueryParam("limit"), 5))
+	if err != nil {
+		if errors.Is(err, article.ErrSearchUnavailable) {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "Suggestions are temporarily unavailable")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to suggest articles due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, suggestions)
+}
+
+// GetArticleByID handles retrieving a single article.
+// @Summary Get an article by ID
+// @Description Retrieves a single news article by its UUID.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID (UUID)"
+// @Success 200 {object} article.Article "Successfully retrieved article"
+// @Failure 404 {object} ErrorResponse "Article not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /articles/{id} [get]
+func (h *Handler) GetArticleByID(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+	}
+
+	found, err := h.articleService.GetArticleByID(e.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, article.ErrArticleNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve article due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, found)
+}
+
+// GetRelatedArticles handles retrieving the articles similar to a given one.
+// @Summary Get related articles
+// @Description Retrieves articles similar in title and body to the given one, most similar first, excluding the article itself.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID (UUID)"
+// @Param limit query int false "Maximum number of related articles (default 5, max 20)"
+// @Param same_author query bool false "Rank articles by the same author higher"
+// @Success 200 {object} article.RelatedArticles "Related articles"
+// @Failure 404 {object} ErrorResponse "Article not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "Related articles are temporarily unavailable"
+// @Router /articles/{id}/related [get]
+func (h *Handler) GetRelatedArticles(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+	}
+	sameAuthor, _ := strconv.ParseBool(e.QueryParam("same_author"))
+
+	related, err := h.articleService.GetRelatedArticles(e.Request().Context(), id, parseIntOrDefault(e.QueryParam("limit"), 5), sameAuthor)
+	if err != nil {
+		if errors.Is(err, article.ErrArticleNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+		}
+		if errors.Is(err, article.ErrSearchUnavailable) {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "Related articles are temporarily unavailable")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve related articles due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, related)
+}
+
+// UpdateArticle handles replacing an existing article.
+// @Summary Replace an article
+// @Description Replaces the title, body, and authors of an existing article and re-indexes it.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID (UUID)"
+// @Param article body article.UpdateArticleRequest true "Full article object"
+// @Success 200 {object} article.Article "Successfully updated article"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, missing fields or invalid authors"
+// @Failure 404 {object} ErrorResponse "Article not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /articles/{id} [put]
+func (h *Handler) UpdateArticle(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+	}
+
+	var req article.UpdateArticleRequest
+	if err := e.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload or malformed JSON")
+	}
+
+	if req.Title == "" || req.Body == "" || (req.Author == "" && len(req.Authors) == 0) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required fields: title, body, and author or authors are mandatory")
+	}
+
+	updatedArticle, err := h.articleService.UpdateArticle(e.Request().Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, article.ErrArticleNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+		}
+		if errors.Is(err, article.ErrInvalidAuthors) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update article due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, updatedArticle)
+}
+
+// PatchArticle handles partially updating an existing article.
+// @Summary Partially update an article
+// @Description Updates only the provided fields of an existing article and re-indexes it.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID (UUID)"
+// @Param article body article.PatchArticleRequest true "Fields to update"
+// @Success 200 {object} article.Article "Successfully updated article"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, empty fields or invalid authors"
+// @Failure 404 {object} ErrorResponse "Article not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /articles/{id} [patch]
+func (h *Handler) PatchArticle(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+	}
+
+	var req article.PatchArticleRequest
+	if err := e.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload or malformed JSON")
+	}
+
+	if req.Title == nil && req.Body == nil && req.Author == nil && req.Authors == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "At least one of title, body, author, or authors must be provided")
+	}
+	if (req.Title != nil && *req.Title == "") || (req.Body != nil && *req.Body == "") || (req.Author != nil && *req.Author == "") {
+		return echo.NewHTTPError(http.StatusBadRequest, "Fields title, body, and author cannot be empty")
+	}
+
+	updatedArticle, err := h.articleService.PatchArticle(e.Request().Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, article.ErrArticleNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+		}
+		if errors.Is(err, article.ErrInvalidAuthors) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update article due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, updatedArticle)
+}
+
+// DeleteArticle handles deleting an article.
+// @Summary Delete an article
+// @Description Deletes an article and removes it from the search index.
+// @Tags articles
+// @Param id path string true "Article ID (UUID)"
+// @Success 204 "Successfully deleted article"
+// @Failure 404 {object} ErrorResponse "Article not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /articles/{id} [delete]
+func (h *Handler) DeleteArticle(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+	}
+
+	err := h.articleService.DeleteArticle(e.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, article.ErrArticleNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Article not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete article due to internal error")
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// CreateSavedSearch handles saving a search to be alerted on.
+// @Summary Save a search
+// @Description Saves a search query. Every new article matching it is sent to the configured alert notifier.
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param saved_search body article.CreateSavedSearchRequest true "Name and query of the search, in the syntax of article searches"
+// @Success 201 {object} article.SavedSearch "Successfully saved search"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, missing fields or malformed query"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /saved-searches [post]
+func (h *Handler) CreateSavedSearch(e echo.Context) error {
+	var req article.CreateSavedSearchRequest
+
+	if err := e.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload or malformed JSON")
+	}
+
+	req.Name, req.Query = strings.TrimSpace(req.Name), strings.TrimSpace(req.Query)
+	if req.Name == "" || req.Query == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required fields: name and query are mandatory")
+	}
+
+	saved, err := h.savedSearchService.CreateSavedSearch(e.Request().Context(), &req)
+	if err != nil {
+		if errors.Is(err, article.ErrInvalidQuery) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save search due to internal error")
+	}
+
+	return e.JSON(http.StatusCreated, saved)
+}
+
+// GetSavedSearches handles listing the saved searches.
+// @Summary List saved searches
+// @Description Retrieves every saved search, newest first.
+// @Tags saved-searches
+// @Produce json
+// @Success 200 {object} article.SavedSearchList "Saved searches"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /saved-searches [get]
+func (h *Handler) GetSavedSearches(e echo.Context) error {
+	list, err := h.savedSearchService.GetSavedSearches(e.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve saved searches due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, list)
+}
+
+// GetSavedSearchByID handles retrieving a single saved search.
+// @Summary Get a saved search
+// @Tags saved-searches
+// @Produce json
+// @Param id path string true "Saved search ID (UUID)"
+// @Success 200 {object} article.SavedSearch "Saved search"
+// @Failure 404 {object} ErrorResponse "Saved search not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /saved-searches/{id} [get]
+func (h *Handler) GetSavedSearchByID(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Saved search not found")
+	}
+
+	saved, err := h.savedSearchService.GetSavedSearchByID(e.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, article.ErrSavedSearchNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Saved search not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve saved search due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, saved)
+}
+
+// DeleteSavedSearch handles deleting a saved search.
+// @Summary Delete a saved search
+// @Description Deletes a saved search and stops its alerts.
+// @Tags saved-searches
+// @Param id path string true "Saved search ID (UUID)"
+// @Success 204 "Successfully deleted saved search"
+// @Failure 404 {object} ErrorResponse "Saved search not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /saved-searches/{id} [delete]
+func (h *Handler) DeleteSavedSearch(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Saved search not found")
+	}
+
+	err := h.savedSearchService.DeleteSavedSearch(e.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, article.ErrSavedSearchNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Saved search not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete saved search due to internal error")
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// CreateAuthor handles the creation of a new author.
+// @Summary Create an author
+// @Description Creates an author ahead of their first article. The slug is derived from the name unless given.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param author body author.CreateAuthorRequest true "Author to be created"
+// @Success 201 {object} author.Author "Successfully created author"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, missing name, invalid slug or avatar_url"
+// @Failure 409 {object} ErrorResponse "An author with this name or slug already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /authors [post]
+func (h *Handler) CreateAuthor(e echo.Context) error {
+	var req author.CreateAuthorRequest
+
+	if err := e.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload or malformed JSON")
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required field: name is mandatory")
+	}
+	if err := validateProfile(req.Slug, req.AvatarURL); err != nil {
+		return err
+	}
+
+	created, err := h.authorService.CreateAuthor(e.Request().Context(), &req)
+	if err != nil {
+		if errors.Is(err, author.ErrAuthorExists) {
+			return echo.NewHTTPError(http.StatusConflict, "An author with this name already exists")
+		}
+		if errors.Is(err, author.ErrSlugExists) {
+			return echo.NewHTTPError(http.StatusConflict, "An author with this slug already exists")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create author due to internal error")
+	}
+
+	return e.JSON(http.StatusCreated, created)
+}
+
+// GetAuthors handles listing the authors.
+// @Summary List authors
+// @Description Retrieves a page of authors ordered by name.
+// @Tags authors
+// @Produce json
+// @Param page query int false "Page number for pagination (default 1)"
+// @Param limit query int false "Number of authors per page (default 10, max 100)"
+// @Success 200 {object} author.AuthorList "Page of authors"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /authors [get]
+func (h *Handler) GetAuthors(e echo.Context) error {
+	page := parseIntOrDefault(e.QueryParam("page"), 1)
+	limit := parseIntOrDefault(e.QueryParam("limit"), 10)
+
+	list, err := h.authorService.GetAuthors(e.Request().Context(), page, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve authors due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, list)
+}
+
+// GetAuthorByID handles retrieving a single author.
+// @Summary Get an author by ID or slug
+// @Tags authors
+// @Produce json
+// @Param id path string true "Author ID (UUID) or slug"
+// @Success 200 {object} author.Author "Author"
+// @Failure 404 {object} ErrorResponse "Author not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /authors/{id} [get]
+func (h *Handler) GetAuthorByID(e echo.Context) error {
+	found, err := h.findAuthor(e, e.Param("id"))
+	if err != nil {
+		if errors.Is(err, author.ErrAuthorNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Author not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve author due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, found)
+}
+
+// UpdateAuthor handles replacing an author's profile.
+// @Summary Update an author
+// @Description Replaces an author's profile, keeping their slug unless given. After a rename their articles are re-indexed under the new name in the background.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Author ID (UUID)"
+// @Param author body author.UpdateAuthorRequest true "New profile of the author"
+// @Success 200 {object} author.Author "Successfully updated author"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, missing name, invalid slug or avatar_url"
+// @Failure 404 {object} ErrorResponse "Author not found"
+// @Failure 409 {object} ErrorResponse "Another author already has this name or slug"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /authors/{id} [put]
+func (h *Handler) UpdateAuthor(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Author not found")
+	}
+
+	var req author.UpdateAuthorRequest
+	if err := e.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload or malformed JSON")
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required field: name is mandatory")
+	}
+	if err := validateProfile(req.Slug, req.AvatarURL); err != nil {
+		return err
+	}
+
+	updated, err := h.authorService.UpdateAuthor(e.Request().Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, author.ErrAuthorNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Author not found")
+		}
+		if errors.Is(err, author.ErrAuthorExists) {
+			return echo.NewHTTPError(http.StatusConflict, "Another author already has this name")
+		}
+		if errors.Is(err, author.ErrSlugExists) {
+			return echo.NewHTTPError(http.StatusConflict, "Another author already has this slug")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update author due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, updated)
+}
+
+// DeleteAuthor handles deleting an author.
+// @Summary Delete an author
+// @Description Deletes an author without articles. Articles are never deleted along with their author, they must be deleted or reassigned first.
+// @Tags authors
+// @Param id path string true "Author ID (UUID)"
+// @Success 204 "Successfully deleted author"
+// @Failure 404 {object} ErrorResponse "Author not found"
+// @Failure 409 {object} ErrorResponse "Author still has articles"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /authors/{id} [delete]
+func (h *Handler) DeleteAuthor(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Author not found")
+	}
+
+	err := h.authorService.DeleteAuthor(e.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, author.ErrAuthorNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Author not found")
+		}
+		if errors.Is(err, author.ErrAuthorHasArticles) {
+			return echo.NewHTTPError(http.StatusConflict, "Author still has articles, delete or reassign them first")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete author due to internal error")
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// MergeAuthors handles merging duplicate authors into a canonical one.
+// @Summary Merge duplicate authors
+// @Description Moves the articles of the duplicate authors to the given author and deletes the duplicates. The moved articles are re-indexed in the background.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Canonical author ID (UUID)"
+// @Param merge body author.MergeAuthorsRequest true "IDs of the duplicate authors"
+// @Success 200 {object} author.MergeResult "Successfully merged authors"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, no duplicates or the author among their own duplicates"
+// @Failure 404 {object} ErrorResponse "Author or duplicate author not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /authors/{id}/merge [post]
+func (h *Handler) MergeAuthors(e echo.Context) error {
+	id := e.Param("id")
+	if !uuidPattern.MatchString(id) {
+		return echo.NewHTTPError(http.StatusNotFound, "Author not found")
+	}
+
+	var req author.MergeAuthorsRequest
+	if err := e.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request payload or malformed JSON")
+	}
+	for _, duplicateID := range req.DuplicateIDs {
+		if !uuidPattern.MatchString(duplicateID) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid duplicate_ids, expected UUIDs")
+		}
+	}
+
+	result, err := h.authorService.MergeAuthors(e.Request().Context(), id, &req)
+	if err != nil {
+		if errors.Is(err, author.ErrInvalidMerge) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, author.ErrAuthorNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Author not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to merge authors due to internal error")
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+// GetAuthorArticles handles listing the articles of one author.
+// @Summary Get an author's articles
+// @Description Retrieves the articles crediting the author with the given slug, in any role, latest first unless sort is given.
+// @Tags authors
+// @Produce json
+// @Param slug path string true "Author slug, or their ID (UUID)"
+// @Param page query int false "Page number for pagination (default 1)"
+// @Param limit query int false "Number of articles per page (default 10, max 100)"
+// @Param cursor query string false "Opaque cursor from a previous next_cursor, replaces page for keyset pagination"
+// @Param sort query string false "Result order: newest or oldest"
+// @Success 200 {object} article.ArticleList "Successfully retrieved page of articles"
+// @Failure 400 {object} ErrorResponse "Invalid cursor or sort"
+// @Failure 404 {object} ErrorResponse "Author not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /authors/{slug}/articles [get]
+func (h *Handler) GetAuthorArticles(e echo.Context) error {
+	found, err := h.findAuthor(e, e.Param("slug"))
+	if err != nil {
+		if errors.Is(err, author.ErrAuthorNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Author not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve author due to internal error")
+	}
+
+	filter := &article.ArticleFilter{
+		Page:      parseIntOrDefault(e.QueryParam("page"), 1),
+		Limit:     parseIntOrDefault(e.QueryParam("limit"), 10),
+		Cursor:    e.QueryParam("cursor"),
+		Sort:      e.QueryParam("sort"),
+		AuthorIDs: []string{found.ID},
 	}
 
 	articles, err := h.articleService.GetArticles(e.Request().Context(), filter)
 	if err != nil {
+		if errors.Is(err, article.ErrInvalidCursor) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid cursor")
+		}
+		if errors.Is(err, article.ErrInvalidSort) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid sort, expected one of: newest, oldest")
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve articles due to internal error")
 	}
+	articles.Links = buildPageLinks(e.Request().URL, articles)
 
 	return e.JSON(http.StatusOK, articles)
 }
 
+// GetMetrics serves the public expvar metrics as JSON, keyed by variable name like expvar does.
+func (h *Handler) GetMetrics(e echo.Context) error {
+	metrics := map[string]json.RawMessage{}
+	for _, name := range publicMetrics {
+		if v := expvar.Get(name); v != nil {
+			metrics[name] = json.RawMessage(v.String())
+		}
+	}
+	return e.JSON(http.StatusOK, metrics)
+}
+
+// findAuthor looks an author up by ID when given a UUID and by slug otherwise.
+// It returns author.ErrAuthorNotFound for a value that is neither.
+func (h *Handler) findAuthor(e echo.Context, idOrSlug string) (*author.Author, error) {
+	if uuidPattern.MatchString(idOrSlug) {
+		return h.authorService.GetAuthorByID(e.Request().Context(), idOrSlug)
+	}
+	if !author.IsValidSlug(idOrSlug) {
+		return nil, author.ErrAuthorNotFound
+	}
+	return h.authorService.GetAuthorBySlug(e.Request().Context(), idOrSlug)
+}
+
+// validateProfile checks the optional slug and avatar URL of an author profile.
+// A slug shaped like a UUID is refused, since it could not be told apart from an ID in URLs.
+func validateProfile(slug, avatarURL string) error {
+	if slug != "" && (!author.IsValidSlug(slug) || uuidPattern.MatchString(slug)) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid slug, expected lowercase letters, digits and single hyphens")
+	}
+	if avatarURL != "" {
+		u, err := url.Parse(avatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid avatar_url, expected an absolute http or https URL")
+		}
+	}
+	return nil
+}
+
 // ErrorResponse represents a standardized error response.
 type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+// buildPageLinks builds the next and previous page URLs, keeping every other query parameter.
+// In cursor mode only a next link exists, since keyset pagination only moves forward.
+func buildPageLinks(u *url.URL, list *article.ArticleList) article.PageLinks {
+	linkURL := func(set map[string]string) string {
+		q := u.Query()
+		q.Del("page")
+		q.Del("cursor")
+		for key, value := range set {
+			q.Set(key, value)
+		}
+		q.Set("limit", strconv.Itoa(list.Limit))
+		return u.Path + "?" + q.Encode()
+	}
+
+	links := article.PageLinks{}
+	if list.Page == 0 {
+		if list.HasNext {
+			links.Next = linkURL(map[string]string{"cursor": list.NextCursor})
+		}
+		return links
+	}
+
+	if list.HasNext {
+		links.Next = linkURL(map[string]string{"page": strconv.Itoa(list.Page + 1)})
+	}
+	if list.Page > 1 {
+		links.Prev = linkURL(map[string]string{"page": strconv.Itoa(list.Page - 1)})
+	}
+	return links
+}
+
+// parseTimeParam parses a date (YYYY-MM-DD, UTC) or an RFC 3339 time, returning the zero time for an empty value.
+// When endOfDay is set a date means the start of the following day, so that an exclusive upper bound includes it.
+func parseTimeParam(s string, endOfDay bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// nonEmpty drops empty values from a repeated query parameter.
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 // parseIntOrDefault parses a string to an int, returning a default value on error.
 func parseIntOrDefault(s string, defaultValue int) int {
 	if s == "" {
@@ -3,6 +3,7 @@ package api_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"kumparan-test/internal/api"
 	"kumparan-test/internal/article"
 	"kumparan-test/internal/author"
@@ -22,7 +23,7 @@ import (
 func TestPostArticle_Success(t *testing.T) {
 	e := echo.New()
 	mockSvc := new(mocks.MockArticleService)
-	handler := api.NewHandler(mockSvc)
+	handler := api.NewHandler(mockSvc, nil, nil)
 
 	reqBody := `{"title":"Test","body":"Content","author":"Bara"}`
 	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(reqBody))
@@ -52,7 +53,7 @@ func TestPostArticle_Success(t *testing.T) {
 
 func TestPostArticle_BadJSON(t *testing.T) {
 	e := echo.New()
-	handler := api.NewHandler(nil)
+	handler := api.NewHandler(nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader("{invalid"))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -65,7 +66,7 @@ func TestPostArticle_BadJSON(t *testing.T) {
 
 func TestPostArticle_MissingFields(t *testing.T) {
 	e := echo.New()
-	handler := api.NewHandler(nil)
+	handler := api.NewHandler(nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title":"T"}`))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -76,10 +77,54 @@ func TestPostArticle_MissingFields(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
 }
 
+func TestPostArticle_WithAuthors(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	reqBody := `{"title":"T","body":"B","authors":[{"name":"Bara"},{"name":"Sari","role":"photographer"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	mockSvc.On("PostArticle", mock.Anything, &article.CreateArticleRequest{
+		Title: "T",
+		Body:  "B",
+		Authors: []article.BylineRequest{
+			{Name: "Bara"},
+			{Name: "Sari", Role: "photographer"},
+		},
+	}).Return(&article.Article{ID: "1"}, nil)
+
+	err := handler.PostArticle(e.NewContext(req, rec))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestPostArticle_InvalidAuthors(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	reqBody := `{"title":"T","body":"B","authors":[{"name":"Bara","role":"illustrator"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	mockSvc.On("PostArticle", mock.Anything, mock.AnythingOfType("*article.CreateArticleRequest")).
+		Return(nil, fmt.Errorf("%w: unknown role \"illustrator\"", article.ErrInvalidAuthors))
+
+	err := handler.PostArticle(e.NewContext(req, rec))
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	assert.Contains(t, err.(*echo.HTTPError).Message, "illustrator")
+}
+
 func TestPostArticle_InternalError(t *testing.T) {
 	e := echo.New()
 	mockSvc := new(mocks.MockArticleService)
-	handler := api.NewHandler(mockSvc)
+	handler := api.NewHandler(mockSvc, nil, nil)
 
 	reqBody := `{"title":"T","body":"B","author":"A"}`
 	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(reqBody))
@@ -99,30 +144,67 @@ func TestPostArticle_InternalError(t *testing.T) {
 func TestGetArticles_Success(t *testing.T) {
 	e := echo.New()
 	mockSvc := new(mocks.MockArticleService)
-	handler := api.NewHandler(mockSvc)
+	handler := api.NewHandler(mockSvc, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/articles?query=test&page=1&limit=2", nil)
 	rec := httptest.NewRecorder()
 	ctx := e.NewContext(req, rec)
 
 	mockSvc.On("GetArticles", mock.Anything, &article.ArticleFilter{
-		Query:  "test",
-		Author: "",
-		Page:   1,
-		Limit:  2,
-	}).Return([]*article.Article{
-		{ID: "1", Title: "T"},
+		Query: "test",
+		Page:  1,
+		Limit: 2,
+	}).Return(&article.ArticleList{
+		Data:    []*article.Article{{ID: "1", Title: "T"}},
+		Total:   5,
+		Page:    1,
+		Limit:   2,
+		HasNext: true,
 	}, nil)
 
 	err := handler.GetArticles(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp article.ArticleList
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, int64(5), resp.Total)
+	assert.True(t, resp.HasNext)
+	assert.Equal(t, "/articles?limit=2&page=2&query=test", resp.Links.Next)
+	assert.Empty(t, resp.Links.Prev)
+}
+
+func TestGetArticles_PrevLinkOnLastPage(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?author=Bara&page=3&limit=2", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, mock.AnythingOfType("*article.ArticleFilter")).
+		Return(&article.ArticleList{
+			Data:  []*article.Article{{ID: "5"}},
+			Total: 5,
+			Page:  3,
+			Limit: 2,
+		}, nil)
+
+	err := handler.GetArticles(ctx)
+	assert.NoError(t, err)
+
+	var resp article.ArticleList
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.Empty(t, resp.Links.Next)
+	assert.Equal(t, "/articles?author=Bara&limit=2&page=2", resp.Links.Prev)
 }
 
 func TestGetArticles_InternalError(t *testing.T) {
 	e := echo.New()
 	mockSvc := new(mocks.MockArticleService)
-	handler := api.NewHandler(mockSvc)
+	handler := api.NewHandler(mockSvc, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/articles?query=err", nil)
 	rec := httptest.NewRecorder()
@@ -139,18 +221,17 @@ func TestGetArticles_InternalError(t *testing.T) {
 func TestGetArticles_InvalidPageLimit(t *testing.T) {
 	e := echo.New()
 	mockSvc := new(mocks.MockArticleService)
-	handler := api.NewHandler(mockSvc)
+	handler := api.NewHandler(mockSvc, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/articles?page=abc&limit=def", nil)
 	rec := httptest.NewRecorder()
 	ctx := e.NewContext(req, rec)
 
 	mockSvc.On("GetArticles", mock.Anything, &article.ArticleFilter{
-		Query:  "",
-		Author: "",
-		Page:   1,
-		Limit:  10,
-	}).Return([]*article.Article{}, nil)
+		Query: "",
+		Page:  1,
+		Limit: 10,
+	}).Return(&article.ArticleList{Data: []*article.Article{}, Page: 1, Limit: 10}, nil)
 
 	err := handler.GetArticles(ctx)
 	assert.NoError(t, err)
@@ -161,7 +242,7 @@ func TestRegisterRoutes_Healthcheck(t *testing.T) {
 	e := echo.New()
 
 	mockSvc := new(mocks.MockArticleService)
-	handler := api.NewHandler(mockSvc)
+	handler := api.NewHandler(mockSvc, nil, nil)
 	handler.RegisterRoutes(e)
 
 	req := httptest.NewRequest(http.MethodGet, "/healthcheck", nil)
@@ -173,10 +254,29 @@ func TestRegisterRoutes_Healthcheck(t *testing.T) {
 	assert.Equal(t, "I'm alive", rec.Body.String())
 }
 
+func TestRegisterRoutes_Metrics(t *testing.T) {
+	e := echo.New()
+
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+	handler.RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "\"article_reconciliation\"")
+	// The command line can carry the database DSN
+	assert.NotContains(t, rec.Body.String(), "\"cmdline\"")
+	assert.NotContains(t, rec.Body.String(), "\"memstats\"")
+}
+
 func TestRegisterRoutes_PostArticle_WiredCorrectly(t *testing.T) {
 	e := echo.New()
 	mockSvc := new(mocks.MockArticleService)
-	handler := api.NewHandler(mockSvc)
+	handler := api.NewHandler(mockSvc, nil, nil)
 	handler.RegisterRoutes(e)
 
 	payload := `{"title":"Test","body":"Content","author":"Bara"}`
@@ -199,3 +299,850 @@ func TestRegisterRoutes_PostArticle_WiredCorrectly(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, rec.Code)
 	mockSvc.AssertExpectations(t)
 }
+
+func TestGetArticleByID_Success(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSvc.On("GetArticleByID", mock.Anything, id).
+		Return(&article.Article{ID: id, Title: "T"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+id, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp article.Article
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.Equal(t, id, resp.ID)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetArticleByID_MalformedID(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+	handler.RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/not-a-uuid", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockSvc.AssertNotCalled(t, "GetArticleByID", mock.Anything, mock.Anything)
+}
+
+func TestGetArticleByID_NotFound(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSvc.On("GetArticleByID", mock.Anything, id).Return(nil, article.ErrArticleNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/"+id, nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id)
+
+	err := handler.GetArticleByID(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestGetArticleByID_InternalError(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSvc.On("GetArticleByID", mock.Anything, id).Return(nil, errors.New("db error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/"+id, nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id)
+
+	err := handler.GetArticleByID(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+}
+
+func TestUpdateArticle_Success(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSvc.On("UpdateArticle", mock.Anything, id, &article.UpdateArticleRequest{Title: "T", Body: "B", Author: "A"}).
+		Return(&article.Article{ID: id, Title: "T"}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/"+id, strings.NewReader(`{"title":"T","body":"B","author":"A"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestUpdateArticle_MissingFields(t *testing.T) {
+	e := echo.New()
+	handler := api.NewHandler(nil, nil, nil)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	req := httptest.NewRequest(http.MethodPut, "/articles/"+id, strings.NewReader(`{"title":"T"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id)
+
+	err := handler.UpdateArticle(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestPatchArticle_Success(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSvc.On("PatchArticle", mock.Anything, id, mock.MatchedBy(func(r *article.PatchArticleRequest) bool {
+		return r.Title != nil && *r.Title == "Fixed" && r.Body == nil && r.Author == nil
+	})).Return(&article.Article{ID: id, Title: "Fixed"}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/articles/"+id, strings.NewReader(`{"title":"Fixed"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestPatchArticle_EmptyBody(t *testing.T) {
+	e := echo.New()
+	handler := api.NewHandler(nil, nil, nil)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	req := httptest.NewRequest(http.MethodPatch, "/articles/"+id, strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id)
+
+	err := handler.PatchArticle(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestPatchArticle_NotFound(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSvc.On("PatchArticle", mock.Anything, id, mock.Anything).Return(nil, article.ErrArticleNotFound)
+
+	req := httptest.NewRequest(http.MethodPatch, "/articles/"+id, strings.NewReader(`{"body":"B"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id)
+
+	err := handler.PatchArticle(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestDeleteArticle_Success(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSvc.On("DeleteArticle", mock.Anything, id).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/"+id, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDeleteArticle_InternalError(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSvc.On("DeleteArticle", mock.Anything, id).Return(errors.New("db error"))
+
+	req := httptest.NewRequest(http.MethodDelete, "/articles/"+id, nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id)
+
+	err := handler.DeleteArticle(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+}
+
+func TestGetArticles_CursorMode(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?cursor=abc&limit=2", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, &article.ArticleFilter{Page: 1, Limit: 2, Cursor: "abc"}).
+		Return(&article.ArticleList{
+			Data:       []*article.Article{{ID: "1"}, {ID: "2"}},
+			Total:      5,
+			Limit:      2,
+			HasNext:    true,
+			NextCursor: "def",
+		}, nil)
+
+	err := handler.GetArticles(ctx)
+	assert.NoError(t, err)
+
+	var resp article.ArticleList
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.Equal(t, "def", resp.NextCursor)
+	assert.Equal(t, "/articles?cursor=def&limit=2", resp.Links.Next)
+	assert.Empty(t, resp.Links.Prev)
+}
+
+func TestGetArticles_InvalidCursor(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?cursor=bogus", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, mock.AnythingOfType("*article.ArticleFilter")).
+		Return(nil, article.ErrInvalidCursor)
+
+	err := handler.GetArticles(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestGetArticles_PassesSort(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?query=banjir&sort=oldest", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 10, Sort: "oldest"}).
+		Return(&article.ArticleList{Data: []*article.Article{{ID: "1"}}, Total: 11, Page: 1, Limit: 10, HasNext: true}, nil)
+
+	err := handler.GetArticles(ctx)
+	assert.NoError(t, err)
+
+	var resp article.ArticleList
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	assert.Equal(t, "/articles?limit=10&page=2&query=banjir&sort=oldest", resp.Links.Next)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetArticles_InvalidSort(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?sort=popular", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, mock.AnythingOfType("*article.ArticleFilter")).
+		Return(nil, article.ErrInvalidSort)
+
+	err := handler.GetArticles(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestGetArticles_SetsSearchBackendHeader(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?query=banjir", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, mock.AnythingOfType("*article.ArticleFilter")).
+		Return(&article.ArticleList{Data: []*article.Article{}, Page: 1, Limit: 10, Backend: article.BackendPostgres}, nil)
+
+	err := handler.GetArticles(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres", rec.Header().Get(api.SearchBackendHeaderKey))
+	assert.NotContains(t, rec.Body.String(), "postgres")
+}
+
+func TestGetArticles_SearchUnavailable(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?query=banjir&cursor=abc", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, mock.AnythingOfType("*article.ArticleFilter")).
+		Return(nil, article.ErrSearchUnavailable)
+
+	err := handler.GetArticles(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, err.(*echo.HTTPError).Code)
+}
+
+func TestGetArticles_ParsesFacets(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?query=banjir&facets=author,date&facet_interval=day", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, &article.ArticleFilter{
+		Query: "banjir", Page: 1, Limit: 10, Facets: []string{"author", "date"}, FacetInterval: "day",
+	}).Return(&article.ArticleList{
+		Data:   []*article.Article{},
+		Page:   1,
+		Limit:  10,
+		Facets: &article.Facets{Authors: []article.FacetBucket{{Key: "Bara", Count: 2}}},
+	}, nil)
+
+	err := handler.GetArticles(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, rec.Body.String(), `"facets":{"authors":[{"key":"Bara","count":2}]}`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetArticles_InvalidFacet(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?query=banjir&facets=tag", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, mock.AnythingOfType("*article.ArticleFilter")).
+		Return(nil, article.ErrInvalidFacet)
+
+	err := handler.GetArticles(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestGetArticles_ParsesFilters(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	authorID := "0b0e4a8e-3f5c-4a57-9d3e-2c1f6a7b8c9d"
+	req := httptest.NewRequest(http.MethodGet, "/articles?author=Bara&author=Sari&author_id="+authorID+"&from=2025-01-01&to=2025-01-31", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, &article.ArticleFilter{
+		Page:      1,
+		Limit:     10,
+		Authors:   []string{"Bara", "Sari"},
+		AuthorIDs: []string{authorID},
+		From:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}).Return(&article.ArticleList{Data: []*article.Article{}, Page: 1, Limit: 10}, nil)
+
+	err := handler.GetArticles(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetArticles_InvalidFilters(t *testing.T) {
+	for _, query := range []string{"from=yesterday", "to=2025-13-01", "author_id=42"} {
+		e := echo.New()
+		mockSvc := new(mocks.MockArticleService)
+		handler := api.NewHandler(mockSvc, nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/articles?"+query, nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+
+		err := handler.GetArticles(ctx)
+		assert.Error(t, err, query)
+		assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code, query)
+		mockSvc.AssertNotCalled(t, "GetArticles", mock.Anything, mock.Anything)
+	}
+}
+
+func TestSuggestArticles_Success(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/suggest?q=ban&limit=3", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("SuggestArticles", mock.Anything, "ban", 3).Return(&article.Suggestions{
+		Articles: []article.ArticleSuggestion{{ID: "1", Title: "Banjir"}},
+		Authors:  []article.AuthorSuggestion{},
+	}, nil)
+
+	err := handler.SuggestArticles(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"articles":[{"id":"1","title":"Banjir"}],"authors":[]}`, rec.Body.String())
+}
+
+func TestSuggestArticles_MissingQuery(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/suggest?q=%20", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	err := handler.SuggestArticles(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestSuggestArticles_Unavailable(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/suggest?q=ban", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("SuggestArticles", mock.Anything, "ban", 5).Return(nil, article.ErrSearchUnavailable)
+
+	err := handler.SuggestArticles(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, err.(*echo.HTTPError).Code)
+}
+
+func TestGetArticles_InvalidQuery(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/articles?query=before:kemarin", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	mockSvc.On("GetArticles", mock.Anything, mock.AnythingOfType("*article.ArticleFilter")).
+		Return(nil, fmt.Errorf("%w: before: expects a date as YYYY-MM-DD", article.ErrInvalidQuery))
+
+	err := handler.GetArticles(ctx)
+	assert.Error(t, err)
+	httpErr := err.(*echo.HTTPError)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, "invalid query: before: expects a date as YYYY-MM-DD", httpErr.Message)
+}
+
+func TestGetRelatedArticles_Success(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	handler := api.NewHandler(mockSvc, nil, nil)
+
+	id := "0b0e4a8e-3f5c-4a57-9d3e-2c1f6a7b8c9d"
+	req := httptest.NewRequest(http.MethodGet, "/articles/"+id+"/related?limit=3&same_author=true", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id)
+
+	mockSvc.On("GetRelatedArticles", mock.Anything, id, 3, true).
+		Return(&article.RelatedArticles{Data: []*article.Article{{ID: "2", Title: "Related"}}}, nil)
+
+	err := handler.GetRelatedArticles(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"title":"Related"`)
+}
+
+func TestGetRelatedArticles_Errors(t *testing.T) {
+	id := "0b0e4a8e-3f5c-4a57-9d3e-2c1f6a7b8c9d"
+	for _, tc := range []struct {
+		err  error
+		code int
+	}{
+		{article.ErrArticleNotFound, http.StatusNotFound},
+		{article.ErrSearchUnavailable, http.StatusServiceUnavailable},
+		{errors.New("boom"), http.StatusInternalServerError},
+	} {
+		e := echo.New()
+		mockSvc := new(mocks.MockArticleService)
+		handler := api.NewHandler(mockSvc, nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/articles/"+id+"/related", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(id)
+
+		mockSvc.On("GetRelatedArticles", mock.Anything, id, 5, false).Return(nil, tc.err)
+
+		err := handler.GetRelatedArticles(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, tc.code, err.(*echo.HTTPError).Code)
+	}
+}
+
+func TestCreateSavedSearch_Success(t *testing.T) {
+	e := echo.New()
+	mockSaved := new(mocks.MockSavedSearchService)
+	handler := api.NewHandler(nil, mockSaved, nil)
+	handler.RegisterRoutes(e)
+
+	mockSaved.On("CreateSavedSearch", mock.Anything, &article.CreateSavedSearchRequest{Name: "Banjir", Query: "banjir jakarta"}).
+		Return(&article.SavedSearch{ID: "search-1", Name: "Banjir", Query: "banjir jakarta"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/saved-searches", strings.NewReader(`{"name":" Banjir ","query":"banjir jakarta"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var resp article.SavedSearch
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "search-1", resp.ID)
+	mockSaved.AssertExpectations(t)
+}
+
+func TestCreateSavedSearch_InvalidRequests(t *testing.T) {
+	e := echo.New()
+	mockSaved := new(mocks.MockSavedSearchService)
+	handler := api.NewHandler(nil, mockSaved, nil)
+	handler.RegisterRoutes(e)
+
+	mockSaved.On("CreateSavedSearch", mock.Anything, &article.CreateSavedSearchRequest{Name: "Banjir", Query: `"banjir`}).
+		Return(nil, fmt.Errorf("%w: unterminated quote", article.ErrInvalidQuery))
+
+	for _, body := range []string{`{invalid`, `{"name":"Banjir"}`, `{"name":" ","query":"banjir"}`, `{"name":"Banjir","query":"\"banjir"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/saved-searches", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestGetSavedSearches(t *testing.T) {
+	e := echo.New()
+	mockSaved := new(mocks.MockSavedSearchService)
+	handler := api.NewHandler(nil, mockSaved, nil)
+	handler.RegisterRoutes(e)
+
+	mockSaved.On("GetSavedSearches", mock.Anything).
+		Return(&article.SavedSearchList{Data: []*article.SavedSearch{{ID: "search-1", Name: "Banjir"}}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/saved-searches", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp article.SavedSearchList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data, 1)
+}
+
+func TestGetSavedSearchByID_NotFound(t *testing.T) {
+	e := echo.New()
+	mockSaved := new(mocks.MockSavedSearchService)
+	handler := api.NewHandler(nil, mockSaved, nil)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockSaved.On("GetSavedSearchByID", mock.Anything, id).Return(nil, article.ErrSavedSearchNotFound)
+
+	for _, path := range []string{"/api/v1/saved-searches/" + id, "/api/v1/saved-searches/not-a-uuid"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
+
+func TestDeleteSavedSearch(t *testing.T) {
+	e := echo.New()
+	mockSaved := new(mocks.MockSavedSearchService)
+	handler := api.NewHandler(nil, mockSaved, nil)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	missing := "0a1b2c3d-4e5f-4b6c-9e7f-3f2b8c1e4d5a"
+	mockSaved.On("DeleteSavedSearch", mock.Anything, id).Return(nil)
+	mockSaved.On("DeleteSavedSearch", mock.Anything, missing).Return(article.ErrSavedSearchNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/saved-searches/"+id, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/saved-searches/"+missing, nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCreateAuthor(t *testing.T) {
+	e := echo.New()
+	mockAuthors := new(mocks.MockAuthorService)
+	handler := api.NewHandler(nil, nil, mockAuthors)
+	handler.RegisterRoutes(e)
+
+	mockAuthors.On("CreateAuthor", mock.Anything, &author.CreateAuthorRequest{Name: "Bara"}).
+		Return(&author.Author{ID: "author-1", Name: "Bara"}, nil)
+	mockAuthors.On("CreateAuthor", mock.Anything, &author.CreateAuthorRequest{Name: "Sekar"}).
+		Return(nil, author.ErrAuthorExists)
+	mockAuthors.On("CreateAuthor", mock.Anything, &author.CreateAuthorRequest{Name: "Dewi", Slug: "dewi"}).
+		Return(nil, author.ErrSlugExists)
+
+	for body, code := range map[string]int{
+		`{"name":" Bara "}`:                                             http.StatusCreated,
+		`{"name":"Sekar"}`:                                              http.StatusConflict,
+		`{"name":"Dewi","slug":"dewi"}`:                                 http.StatusConflict,
+		`{"name":"Dewi","slug":"Dewi Lestari"}`:                         http.StatusBadRequest,
+		`{"name":"Dewi","slug":"3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"}`: http.StatusBadRequest,
+		`{"name":"Dewi","avatar_url":"javascript:alert(1)"}`:            http.StatusBadRequest,
+		`{"name":"Dewi","avatar_url":"/avatars/dewi.png"}`:              http.StatusBadRequest,
+		`{"name":" "}`:                                                  http.StatusBadRequest,
+		`{invalid`:                                                      http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authors", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, code, rec.Code, body)
+	}
+}
+
+func TestGetAuthors(t *testing.T) {
+	e := echo.New()
+	mockAuthors := new(mocks.MockAuthorService)
+	handler := api.NewHandler(nil, nil, mockAuthors)
+	handler.RegisterRoutes(e)
+
+	mockAuthors.On("GetAuthors", mock.Anything, 2, 5).
+		Return(&author.AuthorList{Data: []*author.Author{{ID: "author-1", Name: "Bara"}}, Total: 6, Page: 2, Limit: 5}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authors?page=2&limit=5", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp author.AuthorList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(6), resp.Total)
+	assert.Len(t, resp.Data, 1)
+	mockAuthors.AssertExpectations(t)
+}
+
+func TestGetAuthorByID_NotFound(t *testing.T) {
+	e := echo.New()
+	mockAuthors := new(mocks.MockAuthorService)
+	handler := api.NewHandler(nil, nil, mockAuthors)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockAuthors.On("GetAuthorByID", mock.Anything, id).Return(nil, author.ErrAuthorNotFound)
+	mockAuthors.On("GetAuthorBySlug", mock.Anything, "ghost-writer").Return(nil, author.ErrAuthorNotFound)
+
+	for _, path := range []string{"/api/v1/authors/" + id, "/api/v1/authors/ghost-writer", "/api/v1/authors/Not_A_Slug"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
+
+func TestGetAuthorByID_BySlug(t *testing.T) {
+	e := echo.New()
+	mockAuthors := new(mocks.MockAuthorService)
+	handler := api.NewHandler(nil, nil, mockAuthors)
+	handler.RegisterRoutes(e)
+
+	mockAuthors.On("GetAuthorBySlug", mock.Anything, "bara-putra").Return(&author.Author{
+		ID:            "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f",
+		Name:          "Bara Putra",
+		Slug:          "bara-putra",
+		Bio:           "Jurnalis politik.",
+		SocialHandles: author.SocialHandles{Twitter: "baraputra"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authors/bara-putra", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp author.Author
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "Jurnalis politik.", resp.Bio)
+	assert.Equal(t, "baraputra", resp.SocialHandles.Twitter)
+	mockAuthors.AssertExpectations(t)
+}
+
+func TestGetAuthorArticles(t *testing.T) {
+	e := echo.New()
+	mockSvc := new(mocks.MockArticleService)
+	mockAuthors := new(mocks.MockAuthorService)
+	handler := api.NewHandler(mockSvc, nil, mockAuthors)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	bara := &author.Author{ID: id, Name: "Bara Putra", Slug: "bara-putra"}
+	mockAuthors.On("GetAuthorBySlug", mock.Anything, "bara-putra").Return(bara, nil)
+	mockAuthors.On("GetAuthorBySlug", mock.Anything, "ghost-writer").Return(nil, author.ErrAuthorNotFound)
+	mockSvc.On("GetArticles", mock.Anything, &article.ArticleFilter{Page: 1, Limit: 2, Sort: "oldest", AuthorIDs: []string{id}}).
+		Return(&article.ArticleList{
+			Data:    []*article.Article{{ID: "1", Title: "T", AuthorID: id, Author: *bara}},
+			Total:   3,
+			Page:    1,
+			Limit:   2,
+			HasNext: true,
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/authors/bara-putra/articles?limit=2&sort=oldest", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp article.ArticleList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, "bara-putra", resp.Data[0].Author.Slug)
+	assert.Equal(t, "/api/v1/authors/bara-putra/articles?limit=2&page=2&sort=oldest", resp.Links.Next)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/authors/ghost-writer/articles", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	e := echo.New()
+	mockAuthors := new(mocks.MockAuthorService)
+	handler := api.NewHandler(nil, nil, mockAuthors)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	mockAuthors.On("UpdateAuthor", mock.Anything, id, &author.UpdateAuthorRequest{Name: "Bara Putra"}).
+		Return(&author.Author{ID: id, Name: "Bara Putra"}, nil)
+	mockAuthors.On("UpdateAuthor", mock.Anything, id, &author.UpdateAuthorRequest{Name: "Sekar"}).
+		Return(nil, author.ErrAuthorExists)
+	mockAuthors.On("UpdateAuthor", mock.Anything, id, &author.UpdateAuthorRequest{Name: "Bara", Slug: "sekar"}).
+		Return(nil, author.ErrSlugExists)
+
+	for body, code := range map[string]int{
+		`{"name":"Bara Putra"}`:                   http.StatusOK,
+		`{"name":"Sekar"}`:                        http.StatusConflict,
+		`{"name":"Bara","slug":"sekar"}`:          http.StatusConflict,
+		`{"name":"Bara","slug":"-bara"}`:          http.StatusBadRequest,
+		`{"name":"Bara","avatar_url":"https://"}`: http.StatusBadRequest,
+		`{"name":""}`:                             http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/authors/"+id, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, code, rec.Code, body)
+	}
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	e := echo.New()
+	mockAuthors := new(mocks.MockAuthorService)
+	handler := api.NewHandler(nil, nil, mockAuthors)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	missing := "0a1b2c3d-4e5f-4b6c-9e7f-3f2b8c1e4d5a"
+	busy := "9e7f0a1b-2c3d-4e5f-8a1b-3f2b8c1e4d5a"
+	mockAuthors.On("DeleteAuthor", mock.Anything, id).Return(nil)
+	mockAuthors.On("DeleteAuthor", mock.Anything, missing).Return(author.ErrAuthorNotFound)
+	mockAuthors.On("DeleteAuthor", mock.Anything, busy).Return(author.ErrAuthorHasArticles)
+
+	for authorID, code := range map[string]int{id: http.StatusNoContent, missing: http.StatusNotFound, busy: http.StatusConflict} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/authors/"+authorID, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, code, rec.Code, authorID)
+	}
+}
+
+func TestMergeAuthors(t *testing.T) {
+	e := echo.New()
+	mockAuthors := new(mocks.MockAuthorService)
+	handler := api.NewHandler(nil, nil, mockAuthors)
+	handler.RegisterRoutes(e)
+
+	id := "3f2b8c1e-4d5a-4b6c-9e7f-0a1b2c3d4e5f"
+	duplicate := "0a1b2c3d-4e5f-4b6c-9e7f-3f2b8c1e4d5a"
+	missing := "9e7f0a1b-2c3d-4e5f-8a1b-3f2b8c1e4d5a"
+	mockAuthors.On("MergeAuthors", mock.Anything, id, &author.MergeAuthorsRequest{DuplicateIDs: []string{duplicate}}).
+		Return(&author.MergeResult{Author: &author.Author{ID: id, Name: "Budi Santoso"}, MergedAuthorIDs: []string{duplicate}, ArticlesMoved: 3}, nil)
+	mockAuthors.On("MergeAuthors", mock.Anything, id, &author.MergeAuthorsRequest{DuplicateIDs: []string{missing}}).
+		Return(nil, author.ErrAuthorNotFound)
+	mockAuthors.On("MergeAuthors", mock.Anything, id, &author.MergeAuthorsRequest{DuplicateIDs: []string{id}}).
+		Return(nil, fmt.Errorf("%w: an author cannot be merged into themselves", author.ErrInvalidMerge))
+
+	for body, code := range map[string]int{
+		`{"duplicate_ids":["` + duplicate + `"]}`: http.StatusOK,
+		`{"duplicate_ids":["` + missing + `"]}`:   http.StatusNotFound,
+		`{"duplicate_ids":["` + id + `"]}`:        http.StatusBadRequest,
+		`{"duplicate_ids":["not-a-uuid"]}`:        http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authors/"+id+"/merge", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, code, rec.Code, body)
+		if code == http.StatusOK {
+			var resp author.MergeResult
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, int64(3), resp.ArticlesMoved)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"kumparan-test/internal/article"
+	"kumparan-test/internal/author"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -20,10 +21,154 @@ func (m *MockArticleService) PostArticle(ctx context.Context, req *article.Creat
 	return nil, args.Error(1)
 }
 
-func (m *MockArticleService) GetArticles(ctx context.Context, filter *article.ArticleFilter) ([]*article.Article, error) {
+func (m *MockArticleService) GetArticles(ctx context.Context, filter *article.ArticleFilter) (*article.ArticleList, error) {
 	args := m.Called(ctx, filter)
 	if result := args.Get(0); result != nil {
-		return result.([]*article.Article), args.Error(1)
+		return result.(*article.ArticleList), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockArticleService) SuggestArticles(ctx context.Context, prefix string, limit int) (*article.Suggestions, error) {
+	args := m.Called(ctx, prefix, limit)
+	if result := args.Get(0); result != nil {
+		return result.(*article.Suggestions), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockArticleService) GetRelatedArticles(ctx context.Context, id string, limit int, preferSameAuthor bool) (*article.RelatedArticles, error) {
+	args := m.Called(ctx, id, limit, preferSameAuthor)
+	if result := args.Get(0); result != nil {
+		return result.(*article.RelatedArticles), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockArticleService) GetArticleByID(ctx context.Context, id string) (*article.Article, error) {
+	args := m.Called(ctx, id)
+	if result := args.Get(0); result != nil {
+		return result.(*article.Article), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockArticleService) UpdateArticle(ctx context.Context, id string, req *article.UpdateArticleRequest) (*article.Article, error) {
+	args := m.Called(ctx, id, req)
+	if result := args.Get(0); result != nil {
+		return result.(*article.Article), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockArticleService) PatchArticle(ctx context.Context, id string, req *article.PatchArticleRequest) (*article.Article, error) {
+	args := m.Called(ctx, id, req)
+	if result := args.Get(0); result != nil {
+		return result.(*article.Article), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockArticleService) DeleteArticle(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockSavedSearchService for testing
+type MockSavedSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchService) CreateSavedSearch(ctx context.Context, req *article.CreateSavedSearchRequest) (*article.SavedSearch, error) {
+	args := m.Called(ctx, req)
+	if result := args.Get(0); result != nil {
+		return result.(*article.SavedSearch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchService) GetSavedSearches(ctx context.Context) (*article.SavedSearchList, error) {
+	args := m.Called(ctx)
+	if result := args.Get(0); result != nil {
+		return result.(*article.SavedSearchList), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchService) GetSavedSearchByID(ctx context.Context, id string) (*article.SavedSearch, error) {
+	args := m.Called(ctx, id)
+	if result := args.Get(0); result != nil {
+		return result.(*article.SavedSearch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchService) DeleteSavedSearch(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockAuthorService struct {
+	mock.Mock
+}
+
+func (m *MockAuthorService) GetOrCreateAuthor(ctx context.Context, name string) (*author.Author, error) {
+	args := m.Called(ctx, name)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) GetAuthors(ctx context.Context, page, limit int) (*author.AuthorList, error) {
+	args := m.Called(ctx, page, limit)
+	if result := args.Get(0); result != nil {
+		return result.(*author.AuthorList), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) GetAuthorByID(ctx context.Context, id string) (*author.Author, error) {
+	args := m.Called(ctx, id)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) GetAuthorBySlug(ctx context.Context, slug string) (*author.Author, error) {
+	args := m.Called(ctx, slug)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) CreateAuthor(ctx context.Context, req *author.CreateAuthorRequest) (*author.Author, error) {
+	args := m.Called(ctx, req)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) UpdateAuthor(ctx context.Context, id string, req *author.UpdateAuthorRequest) (*author.Author, error) {
+	args := m.Called(ctx, id, req)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) DeleteAuthor(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthorService) MergeAuthors(ctx context.Context, canonicalID string, req *author.MergeAuthorsRequest) (*author.MergeResult, error) {
+	args := m.Called(ctx, canonicalID, req)
+	if result := args.Get(0); result != nil {
+		return result.(*author.MergeResult), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
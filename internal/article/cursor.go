@@ -0,0 +1,42 @@
+package article
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// Cursor marks the position of the last article of a page in keyset pagination.
+// PostgreSQL listings use CreatedAt and ID; Elasticsearch searches use the raw sort values of the last hit.
+// Sort records the order the cursor was issued for, since positions are meaningless under another order.
+type Cursor struct {
+	Sort        string        `json:"s,omitempty"`
+	CreatedAt   time.Time     `json:"t,omitempty"`
+	ID          string        `json:"id,omitempty"`
+	SearchAfter []interface{} `json:"sa,omitempty"`
+}
+
+// encodeCursor turns a cursor into the opaque string handed to clients.
+func encodeCursor(cursor *Cursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor parses a cursor produced by encodeCursor.
+func decodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep sort values as json.Number so large longs are sent back to Elasticsearch unchanged
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var cursor Cursor
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,161 @@
+package article
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kumparan-test/internal/author"
+	"kumparan-test/internal/outbox"
+	"kumparan-test/pkg/search"
+
+	"github.com/sirupsen/logrus"
+)
+
+// authorReindexBatchSize is how many articles of a renamed author are re-indexed per bulk request.
+const authorReindexBatchSize = 500
+
+// Indexer keeps the Elasticsearch articles and saved searches indices in sync by handling
+// article, author and saved search outbox events, and alerts the saved searches matching new articles.
+type Indexer struct {
+	repo          Repository
+	savedSearches SavedSearchRepository
+	esClient      search.SearchService
+	notifier      Notifier
+}
+
+// NewIndexer creates a new outbox handler for article, author and saved search events.
+func NewIndexer(repo Repository, savedSearches SavedSearchRepository, esClient search.SearchService, notifier Notifier) *Indexer {
+	return &Indexer{
+		repo:          repo,
+		savedSearches: savedSearches,
+		esClient:      esClient,
+		notifier:      notifier,
+	}
+}
+
+// Handle applies a single article, author or saved search event to the search index.
+// The article is re-read from PostgreSQL so that retried or out-of-order events
+// always converge on the current database state.
+func (i *Indexer) Handle(ctx context.Context, event *outbox.Event) error {
+	switch event.EventType {
+	case EventArticleCreated, EventArticleUpdated:
+		article, err := i.repo.GetArticleByID(ctx, event.AggregateID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Deleted before this event was delivered
+			return i.esClient.DeleteDocument(ctx, search.ArticleIndexName, event.AggregateID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load article: %w", err)
+		}
+		if event.EventType == EventArticleUpdated {
+			return i.esClient.UpdateDocument(ctx, search.ArticleIndexName, article.ID, newSearchDocument(article))
+		}
+		if err := i.esClient.IndexDocument(ctx, search.ArticleIndexName, article.ID, newSearchDocument(article)); err != nil {
+			return err
+		}
+		return i.alert(ctx, article)
+	case EventArticleDeleted:
+		return i.esClient.DeleteDocument(ctx, search.ArticleIndexName, event.AggregateID)
+	case author.EventAuthorUpdated:
+		return i.reindexAuthor(ctx, event.AggregateID)
+	case EventSavedSearchCreated:
+		saved, err := i.savedSearches.GetSavedSearchByID(ctx, event.AggregateID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return i.esClient.DeleteDocument(ctx, search.SavedSearchIndexName, event.AggregateID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load saved search: %w", err)
+		}
+		doc, err := newSavedSearchDocument(saved)
+		if err != nil {
+			return err
+		}
+		return i.esClient.IndexDocument(ctx, search.SavedSearchIndexName, saved.ID, doc)
+	case EventSavedSearchDeleted:
+		return i.esClient.DeleteDocument(ctx, search.SavedSearchIndexName, event.AggregateID)
+	default:
+		return fmt.Errorf("unknown outbox event type %q", event.EventType)
+	}
+}
+
+// reindexAuthor re-indexes every article crediting an author, so that they are found under the author's current name.
+func (i *Indexer) reindexAuthor(ctx context.Context, authorID string) error {
+	filter := &ArticleFilter{AuthorIDs: []string{authorID}, Limit: authorReindexBatchSize, Sort: SortOldest}
+	var cursor *Cursor
+
+	for {
+		articles, hasNext, err := i.repo.GetArticlesAfter(ctx, filter, cursor)
+		if err != nil {
+			return fmt.Errorf("failed to load author articles: %w", err)
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		docs := make(map[string]interface{}, len(articles))
+		for _, a := range articles {
+			docs[a.ID] = newSearchDocument(a)
+		}
+		if err := i.esClient.BulkIndexDocuments(ctx, search.ArticleIndexName, docs); err != nil {
+			return err
+		}
+
+		if !hasNext {
+			return nil
+		}
+		last := articles[len(articles)-1]
+		cursor = &Cursor{Sort: SortOldest, CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// alert percolates a new article against the saved searches and notifies each one it matches.
+// A failed percolation is returned so the event is retried; nobody was notified yet and indexing
+// again is harmless. A failed notification is only logged, retrying would repeat the others.
+func (i *Indexer) alert(ctx context.Context, article *Article) error {
+	ids, err := i.esClient.Percolate(ctx, search.SavedSearchIndexName, newSearchDocument(article))
+	if err != nil {
+		return fmt.Errorf("failed to match saved searches: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Saved searches deleted before their percolator document was removed are skipped
+	matched, err := i.savedSearches.GetSavedSearchesByID(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load matching saved searches: %w", err)
+	}
+
+	for _, saved := range matched {
+		if err := i.notifier.Notify(ctx, &Alert{SavedSearch: saved, Article: article}); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"saved_search_id": saved.ID,
+				"article_id":      article.ID,
+			}).Error("Failed to notify saved search alert")
+		}
+	}
+	return nil
+}
+
+// newSearchDocument builds the Elasticsearch document for an article, with the names, IDs and name keys of all its bylines.
+func newSearchDocument(article *Article) *search.ArticleDocument {
+	doc := &search.ArticleDocument{
+		ID:         article.ID,
+		Title:      article.Title,
+		Body:       article.Body,
+		Authors:    []string{},
+		AuthorIDs:  []string{},
+		AuthorKeys: []string{},
+		CreatedAt:  article.CreatedAt,
+		UpdatedAt:  article.UpdatedAt,
+	}
+	for _, byline := range article.Authors {
+		doc.Authors = append(doc.Authors, byline.Name)
+		doc.AuthorIDs = append(doc.AuthorIDs, byline.ID)
+		doc.AuthorKeys = append(doc.AuthorKeys, author.NameKey(byline.Name))
+	}
+	doc.Checksum = doc.ContentChecksum()
+	return doc
+}
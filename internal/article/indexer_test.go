@@ -0,0 +1,233 @@
+package article_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"testing"
+
+	"kumparan-test/internal/article"
+	"kumparan-test/internal/article/mocks"
+	"kumparan-test/internal/author"
+	"kumparan-test/internal/outbox"
+	"kumparan-test/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIndexer_CreatedEventIndexesCurrentState(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(mockRepo, new(mocks.MockSavedSearchRepo), mockSearch, new(mocks.MockNotifier))
+
+	bara := author.Author{ID: "auth-1", Name: "Bara"}
+	stored := &article.Article{ID: "art-1", Title: "Hello", Body: "World", Author: bara, Authors: article.Bylines{
+		{Author: bara, Role: article.RoleWriter},
+		{Author: author.Author{ID: "auth-2", Name: "Sari Dewi"}, Role: article.RolePhotographer},
+	}}
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(stored, nil)
+	mockSearch.On("IndexDocument", mock.Anything, search.ArticleIndexName, "art-1", mock.MatchedBy(func(doc *search.ArticleDocument) bool {
+		return doc.Title == "Hello" && assert.ObjectsAreEqual([]string{"Bara", "Sari Dewi"}, doc.Authors) &&
+			assert.ObjectsAreEqual([]string{"auth-1", "auth-2"}, doc.AuthorIDs) &&
+			assert.ObjectsAreEqual([]string{"bara", "sari dewi"}, doc.AuthorKeys) &&
+			doc.Checksum != "" && doc.Checksum == doc.ContentChecksum()
+	})).Return(nil)
+	mockSearch.On("Percolate", mock.Anything, search.SavedSearchIndexName, mock.Anything).
+		Return([]string{}, nil)
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "art-1", EventType: article.EventArticleCreated})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestIndexer_UpdatedEventForDeletedArticleRemovesDocument(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(mockRepo, new(mocks.MockSavedSearchRepo), mockSearch, new(mocks.MockNotifier))
+
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(nil, sql.ErrNoRows)
+	mockSearch.On("DeleteDocument", mock.Anything, search.ArticleIndexName, "art-1").Return(nil)
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "art-1", EventType: article.EventArticleUpdated})
+
+	assert.NoError(t, err)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestIndexer_DeletedEvent(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(mockRepo, new(mocks.MockSavedSearchRepo), mockSearch, new(mocks.MockNotifier))
+
+	mockSearch.On("DeleteDocument", mock.Anything, search.ArticleIndexName, "art-1").Return(nil)
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "art-1", EventType: article.EventArticleDeleted})
+
+	assert.NoError(t, err)
+	mockSearch.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetArticleByID", mock.Anything, mock.Anything)
+}
+
+func TestIndexer_ESFailureIsReturnedForRetry(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(mockRepo, new(mocks.MockSavedSearchRepo), mockSearch, new(mocks.MockNotifier))
+
+	stored := &article.Article{ID: "art-1", Title: "Hello"}
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(stored, nil)
+	mockSearch.On("UpdateDocument", mock.Anything, search.ArticleIndexName, "art-1", mock.Anything).
+		Return(fmt.Errorf("ES unavailable"))
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "art-1", EventType: article.EventArticleUpdated})
+
+	assert.Error(t, err)
+}
+
+func TestIndexer_UnknownEventType(t *testing.T) {
+	indexer := article.NewIndexer(new(mocks.MockRepo), new(mocks.MockSavedSearchRepo), new(mocks.MockSearchService), new(mocks.MockNotifier))
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "art-1", EventType: "article.exploded"})
+
+	assert.Error(t, err)
+}
+
+func TestIndexer_CreatedEventAlertsMatchingSavedSearches(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSaved := new(mocks.MockSavedSearchRepo)
+	mockSearch := new(mocks.MockSearchService)
+	mockNotifier := new(mocks.MockNotifier)
+	indexer := article.NewIndexer(mockRepo, mockSaved, mockSearch, mockNotifier)
+
+	stored := &article.Article{ID: "art-1", Title: "Banjir Jakarta", Author: author.Author{ID: "auth-1", Name: "Bara"}}
+	banjir := &article.SavedSearch{ID: "search-1", Name: "Banjir", Query: "banjir"}
+	jakarta := &article.SavedSearch{ID: "search-2", Name: "Jakarta", Query: "jakarta"}
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(stored, nil)
+	mockSearch.On("IndexDocument", mock.Anything, search.ArticleIndexName, "art-1", mock.Anything).Return(nil)
+	mockSearch.On("Percolate", mock.Anything, search.SavedSearchIndexName, mock.MatchedBy(func(doc *search.ArticleDocument) bool {
+		return doc.ID == "art-1" && doc.Title == "Banjir Jakarta"
+	})).Return([]string{"search-1", "search-2", "search-deleted"}, nil)
+	mockSaved.On("GetSavedSearchesByID", mock.Anything, []string{"search-1", "search-2", "search-deleted"}).
+		Return([]*article.SavedSearch{banjir, jakarta}, nil)
+	mockNotifier.On("Notify", mock.Anything, &article.Alert{SavedSearch: banjir, Article: stored}).Return(fmt.Errorf("webhook down"))
+	mockNotifier.On("Notify", mock.Anything, &article.Alert{SavedSearch: jakarta, Article: stored}).Return(nil)
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "art-1", EventType: article.EventArticleCreated})
+
+	// A failed notification does not fail the event, retrying would notify the others again
+	assert.NoError(t, err)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestIndexer_PercolationFailureIsReturnedForRetry(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	mockNotifier := new(mocks.MockNotifier)
+	indexer := article.NewIndexer(mockRepo, new(mocks.MockSavedSearchRepo), mockSearch, mockNotifier)
+
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(&article.Article{ID: "art-1"}, nil)
+	mockSearch.On("IndexDocument", mock.Anything, search.ArticleIndexName, "art-1", mock.Anything).Return(nil)
+	mockSearch.On("Percolate", mock.Anything, search.SavedSearchIndexName, mock.Anything).
+		Return([]string(nil), fmt.Errorf("ES unavailable"))
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "art-1", EventType: article.EventArticleCreated})
+
+	assert.Error(t, err)
+	mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+}
+
+func TestIndexer_UpdatedEventDoesNotAlert(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(mockRepo, new(mocks.MockSavedSearchRepo), mockSearch, new(mocks.MockNotifier))
+
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(&article.Article{ID: "art-1"}, nil)
+	mockSearch.On("UpdateDocument", mock.Anything, search.ArticleIndexName, "art-1", mock.Anything).Return(nil)
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "art-1", EventType: article.EventArticleUpdated})
+
+	assert.NoError(t, err)
+	mockSearch.AssertNotCalled(t, "Percolate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIndexer_SavedSearchCreatedEventRegistersQuery(t *testing.T) {
+	mockSaved := new(mocks.MockSavedSearchRepo)
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(new(mocks.MockRepo), mockSaved, mockSearch, new(mocks.MockNotifier))
+
+	mockSaved.On("GetSavedSearchByID", mock.Anything, "search-1").
+		Return(&article.SavedSearch{ID: "search-1", Name: "Banjir", Query: "banjir jakarta"}, nil)
+	mockSearch.On("IndexDocument", mock.Anything, search.SavedSearchIndexName, "search-1", mock.MatchedBy(func(doc *search.SavedSearchDocument) bool {
+		query, ok := doc.Query.(*search.BoolQuery)
+		return ok && len(query.Must) == 1 && query.Must[0].(*search.MatchQuery).Text == "banjir jakarta"
+	})).Return(nil)
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "search-1", EventType: article.EventSavedSearchCreated})
+
+	assert.NoError(t, err)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestIndexer_SavedSearchCreatedEventForDeletedSearchRemovesQuery(t *testing.T) {
+	mockSaved := new(mocks.MockSavedSearchRepo)
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(new(mocks.MockRepo), mockSaved, mockSearch, new(mocks.MockNotifier))
+
+	mockSaved.On("GetSavedSearchByID", mock.Anything, "search-1").Return(nil, sql.ErrNoRows)
+	mockSearch.On("DeleteDocument", mock.Anything, search.SavedSearchIndexName, "search-1").Return(nil)
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "search-1", EventType: article.EventSavedSearchCreated})
+
+	assert.NoError(t, err)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestIndexer_SavedSearchDeletedEvent(t *testing.T) {
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(new(mocks.MockRepo), new(mocks.MockSavedSearchRepo), mockSearch, new(mocks.MockNotifier))
+
+	mockSearch.On("DeleteDocument", mock.Anything, search.SavedSearchIndexName, "search-1").Return(nil)
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "search-1", EventType: article.EventSavedSearchDeleted})
+
+	assert.NoError(t, err)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestIndexer_AuthorUpdatedEventReindexesTheirArticles(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	indexer := article.NewIndexer(mockRepo, new(mocks.MockSavedSearchRepo), mockSearch, new(mocks.MockNotifier))
+
+	renamed := author.Author{ID: "auth-1", Name: "Bara Putra"}
+	sari := author.Author{ID: "auth-2", Name: "Sari"}
+	wrote := article.Bylines{{Author: renamed, Role: article.RoleWriter}}
+	// Articles they are credited on after another author are re-indexed too
+	edited := article.Bylines{{Author: sari, Role: article.RoleWriter}, {Author: renamed, Role: article.RoleEditor}}
+	first := []*article.Article{{ID: "art-1", Author: renamed, Authors: wrote}, {ID: "art-2", Author: sari, Authors: edited}}
+	second := []*article.Article{{ID: "art-3", Author: renamed, Authors: wrote}}
+	byAuthor := mock.MatchedBy(func(f *article.ArticleFilter) bool {
+		return len(f.AuthorIDs) == 1 && f.AuthorIDs[0] == "auth-1"
+	})
+	mockRepo.On("GetArticlesAfter", mock.Anything, byAuthor, (*article.Cursor)(nil)).Return(first, true, nil)
+	mockRepo.On("GetArticlesAfter", mock.Anything, byAuthor, mock.MatchedBy(func(c *article.Cursor) bool {
+		return c != nil && c.ID == "art-2"
+	})).Return(second, false, nil)
+	mockSearch.On("BulkIndexDocuments", mock.Anything, search.ArticleIndexName, mock.MatchedBy(func(docs map[string]interface{}) bool {
+		for _, doc := range docs {
+			if !slices.Contains(doc.(*search.ArticleDocument).Authors, "Bara Putra") {
+				return false
+			}
+		}
+		return true
+	})).Return(nil).Twice()
+
+	err := indexer.Handle(context.Background(), &outbox.Event{AggregateID: "auth-1", EventType: author.EventAuthorUpdated})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockSearch.AssertExpectations(t)
+}
@@ -2,11 +2,12 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"kumparan-test/internal/article"
 	"kumparan-test/internal/author"
+	"kumparan-test/pkg/search"
 
-	"github.com/olivere/elastic/v7"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -19,16 +20,121 @@ func (m *MockRepo) CreateArticle(ctx context.Context, art *article.Article) (*ar
 	return args.Get(0).(*article.Article), args.Error(1)
 }
 
-func (m *MockRepo) GetArticles(ctx context.Context, filter *article.ArticleFilter) ([]*article.Article, error) {
+func (m *MockRepo) GetArticles(ctx context.Context, filter *article.ArticleFilter) ([]*article.Article, int64, error) {
 	args := m.Called(ctx, filter)
+	return args.Get(0).([]*article.Article), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepo) GetArticlesAfter(ctx context.Context, filter *article.ArticleFilter, cursor *article.Cursor) ([]*article.Article, bool, error) {
+	args := m.Called(ctx, filter, cursor)
+	return args.Get(0).([]*article.Article), args.Bool(1), args.Error(2)
+}
+
+func (m *MockRepo) CountArticles(ctx context.Context, filter *article.ArticleFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) GetArticlesInIDOrder(ctx context.Context, afterID string, limit int) ([]*article.Article, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*article.Article), args.Error(1)
+}
+
+func (m *MockRepo) GetArticlesChangedSince(ctx context.Context, since time.Time, afterID string, limit int) ([]*article.Article, error) {
+	args := m.Called(ctx, since, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*article.Article), args.Error(1)
 }
 
-func (m *MockRepo) GetArticlesByID(ctx context.Context, filter *article.ArticleFilter, ids []string) ([]*article.Article, error) {
-	args := m.Called(ctx, filter, ids)
+func (m *MockRepo) GetDeletedArticleIDs(ctx context.Context, since time.Time) ([]string, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepo) GetArticlesByID(ctx context.Context, ids []string) ([]*article.Article, error) {
+	args := m.Called(ctx, ids)
 	return args.Get(0).([]*article.Article), args.Error(1)
 }
 
+func (m *MockRepo) GetArticleByID(ctx context.Context, id string) (*article.Article, error) {
+	args := m.Called(ctx, id)
+	if a := args.Get(0); a != nil {
+		return a.(*article.Article), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepo) UpdateArticle(ctx context.Context, art *article.Article) (*article.Article, error) {
+	args := m.Called(ctx, art)
+	if a := args.Get(0); a != nil {
+		return a.(*article.Article), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepo) DeleteArticle(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockSavedSearchRepo struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchRepo) CreateSavedSearch(ctx context.Context, saved *article.SavedSearch) (*article.SavedSearch, error) {
+	args := m.Called(ctx, saved)
+	if s := args.Get(0); s != nil {
+		return s.(*article.SavedSearch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) GetSavedSearches(ctx context.Context) ([]*article.SavedSearch, error) {
+	args := m.Called(ctx)
+	if s := args.Get(0); s != nil {
+		return s.([]*article.SavedSearch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) GetSavedSearchesByID(ctx context.Context, ids []string) ([]*article.SavedSearch, error) {
+	args := m.Called(ctx, ids)
+	if s := args.Get(0); s != nil {
+		return s.([]*article.SavedSearch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) GetSavedSearchByID(ctx context.Context, id string) (*article.SavedSearch, error) {
+	args := m.Called(ctx, id)
+	if s := args.Get(0); s != nil {
+		return s.(*article.SavedSearch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) DeleteSavedSearch(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, alert *article.Alert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
 type MockAuthorService struct {
 	mock.Mock
 }
@@ -38,6 +144,59 @@ func (m *MockAuthorService) GetOrCreateAuthor(ctx context.Context, name string)
 	return args.Get(0).(*author.Author), args.Error(1)
 }
 
+func (m *MockAuthorService) GetAuthors(ctx context.Context, page, limit int) (*author.AuthorList, error) {
+	args := m.Called(ctx, page, limit)
+	if result := args.Get(0); result != nil {
+		return result.(*author.AuthorList), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) GetAuthorByID(ctx context.Context, id string) (*author.Author, error) {
+	args := m.Called(ctx, id)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) GetAuthorBySlug(ctx context.Context, slug string) (*author.Author, error) {
+	args := m.Called(ctx, slug)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) CreateAuthor(ctx context.Context, req *author.CreateAuthorRequest) (*author.Author, error) {
+	args := m.Called(ctx, req)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) UpdateAuthor(ctx context.Context, id string, req *author.UpdateAuthorRequest) (*author.Author, error) {
+	args := m.Called(ctx, id, req)
+	if result := args.Get(0); result != nil {
+		return result.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorService) DeleteAuthor(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthorService) MergeAuthors(ctx context.Context, canonicalID string, req *author.MergeAuthorsRequest) (*author.MergeResult, error) {
+	args := m.Called(ctx, canonicalID, req)
+	if result := args.Get(0); result != nil {
+		return result.(*author.MergeResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 type MockSearchService struct {
 	mock.Mock
 }
@@ -47,9 +206,52 @@ func (m *MockSearchService) IndexDocument(ctx context.Context, indexName, id str
 	return args.Error(0)
 }
 
-func (m *MockSearchService) SearchDocuments(ctx context.Context, indexName string, query elastic.Query, from, size int, sortAsc bool, by string) (*elastic.SearchResult, error) {
-	args := m.Called(ctx, indexName, query, from, size, sortAsc, by)
-	return args.Get(0).(*elastic.SearchResult), args.Error(1)
+func (m *MockSearchService) UpdateDocument(ctx context.Context, indexName, id string, doc interface{}) error {
+	args := m.Called(ctx, indexName, id, doc)
+	return args.Error(0)
+}
+
+func (m *MockSearchService) DeleteDocument(ctx context.Context, indexName, id string) error {
+	args := m.Called(ctx, indexName, id)
+	return args.Error(0)
+}
+
+func (m *MockSearchService) SearchDocuments(ctx context.Context, indexName string, query search.Query, opts search.SearchOptions) (*search.SearchResult, error) {
+	args := m.Called(ctx, indexName, query, opts)
+	return args.Get(0).(*search.SearchResult), args.Error(1)
+}
+
+func (m *MockSearchService) Percolate(ctx context.Context, indexName string, doc interface{}) ([]string, error) {
+	args := m.Called(ctx, indexName, doc)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockSearchService) BulkIndexDocuments(ctx context.Context, indexName string, docs map[string]interface{}) error {
+	args := m.Called(ctx, indexName, docs)
+	return args.Error(0)
+}
+
+func (m *MockSearchService) IndexExists(ctx context.Context, indexName string) (bool, error) {
+	args := m.Called(ctx, indexName)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSearchService) CreateIndex(ctx context.Context, indexName string, body string) error {
+	args := m.Called(ctx, indexName, body)
+	return args.Error(0)
+}
+
+func (m *MockSearchService) DeleteIndex(ctx context.Context, indexName string) error {
+	args := m.Called(ctx, indexName)
+	return args.Error(0)
+}
+
+func (m *MockSearchService) SwitchAlias(ctx context.Context, alias string, indexName string) ([]string, error) {
+	args := m.Called(ctx, alias, indexName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
 }
 
 func (m *MockSearchService) Close() {
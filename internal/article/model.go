@@ -1,31 +1,252 @@
 package article
 
 import (
+	"encoding/json"
+	"fmt"
 	"kumparan-test/internal/author"
 	"time"
 )
 
+// Outbox event types emitted by article writes.
+const (
+	EventArticleCreated = "article.created"
+	EventArticleUpdated = "article.updated"
+	EventArticleDeleted = "article.deleted"
+)
+
+// Outbox event types emitted by saved search writes.
+const (
+	EventSavedSearchCreated = "saved_search.created"
+	EventSavedSearchDeleted = "saved_search.deleted"
+)
+
+// Backends that can answer article listings.
+const (
+	BackendElasticsearch = "elasticsearch"
+	BackendMemory        = "memory"
+	BackendPostgres      = "postgres"
+)
+
+// Facets that can be requested on a search.
+const (
+	FacetAuthor = "author"
+	FacetDate   = "date"
+)
+
+// Date facet intervals.
+const (
+	FacetIntervalDay   = "day"
+	FacetIntervalWeek  = "week"
+	FacetIntervalMonth = "month"
+)
+
+// Sort orders accepted by article listings.
+const (
+	SortRelevance = "relevance" // Best search match first, only meaningful with a query
+	SortNewest    = "newest"
+	SortOldest    = "oldest"
+)
+
+// Roles an author can be credited with on an article.
+const (
+	RoleWriter       = "writer"
+	RoleEditor       = "editor"
+	RolePhotographer = "photographer"
+)
+
 // Article represents the structure of a news article.
+// Author is the lead author, the first of the bylines.
 type Article struct {
-	ID        string        `json:"id"`
-	Title     string        `json:"title"`
-	Body      string        `json:"body"`
-	AuthorID  string        `json:"author_id,omitempty"`
-	Author    author.Author `json:"author"`
-	CreatedAt time.Time     `json:"created_at"`
+	ID         string              `json:"id"`
+	Title      string              `json:"title"`
+	Body       string              `json:"body"`
+	AuthorID   string              `json:"author_id,omitempty"`
+	Author     author.Author       `json:"author"`
+	Authors    Bylines             `json:"authors"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+	Score      *float64            `json:"score,omitempty"`      // Search relevance, only set on search results
+	Highlights map[string][]string `json:"highlights,omitempty"` // Matching fragments by field, only set on search results
+}
+
+// Byline credits an author on an article with their role.
+type Byline struct {
+	author.Author
+	Role string `json:"role"`
+}
+
+// Bylines are the authors credited on an article, in credit order.
+type Bylines []Byline
+
+// Scan reads the bylines aggregated into a JSON array by PostgreSQL.
+func (b *Bylines) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*b = Bylines{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	default:
+		return fmt.Errorf("cannot scan %T into Bylines", src)
+	}
+}
+
+// setBylines credits the authors of an article, the first one becoming its lead author.
+func (a *Article) setBylines(bylines Bylines) {
+	a.Authors = bylines
+	a.Author = bylines[0].Author
+	a.AuthorID = a.Author.ID
+}
+
+// creditsOnly tells whether the article is credited to a single writer with the given name.
+func (a *Article) creditsOnly(name string) bool {
+	return len(a.Authors) == 1 && a.Authors[0].Role == RoleWriter && a.Authors[0].Name == name
+}
+
+// ArticleVersion identifies the indexed content of an article by the checksum of its search document,
+// used to detect stale search documents.
+type ArticleVersion struct {
+	ID       string
+	Checksum string
+}
+
+// BylineRequest credits an author, by name, on a new or updated article.
+type BylineRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"` // writer, editor or photographer (default writer)
 }
 
 // CreateArticleRequest represents the request body for creating a new article.
+// The authors are credited in the given order; author is a shorthand for a single writer.
 type CreateArticleRequest struct {
-	Title  string `json:"title"`
-	Body   string `json:"body"`
-	Author string `json:"author"`
+	Title   string          `json:"title"`
+	Body    string          `json:"body"`
+	Author  string          `json:"author,omitempty"`
+	Authors []BylineRequest `json:"authors,omitempty"`
+}
+
+// UpdateArticleRequest represents the request body for replacing an article (PUT).
+// Like on creation, either author or authors is given.
+type UpdateArticleRequest struct {
+	Title   string          `json:"title"`
+	Body    string          `json:"body"`
+	Author  string          `json:"author,omitempty"`
+	Authors []BylineRequest `json:"authors,omitempty"`
+}
+
+// PatchArticleRequest represents the request body for partially updating an article (PATCH).
+// Nil fields are left unchanged. Author or authors replace every byline.
+type PatchArticleRequest struct {
+	Title   *string         `json:"title"`
+	Body    *string         `json:"body"`
+	Author  *string         `json:"author"`
+	Authors []BylineRequest `json:"authors"`
 }
 
 // ArticleFilter represents the optional query parameters for listing articles.
 type ArticleFilter struct {
 	Query  string // Keywords to search in title and body
-	Author string // Filter by author's name
 	Page   int    // For pagination (default 1)
 	Limit  int    // For pagination (default 10)
+	Cursor string // Opaque keyset cursor, takes precedence over Page when set
+	Sort   string // relevance, newest or oldest (default relevance with a query, newest without)
+
+	Authors   []string  // Only articles crediting one of these author names
+	AuthorIDs []string  // Only articles crediting one of these author IDs
+	From      time.Time // Only articles created at or after this time, ignored when zero
+	To        time.Time // Only articles created before this time, ignored when zero
+
+	Facets        []string // Facets to compute on a search: author and/or date
+	FacetInterval string   // Bucket size of the date facet: day, week or month (default month)
+}
+
+// nameKeys returns the author.NameKey of each name, the form author name filters are matched in.
+func nameKeys(names []string) []string {
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = author.NameKey(name)
+	}
+	return keys
+}
+
+// ArticleList is a page of articles together with its pagination metadata.
+// Page is omitted in cursor mode, where NextCursor is the way forward.
+type ArticleList struct {
+	Data       []*Article `json:"data"`
+	Total      int64      `json:"total"`
+	Page       int        `json:"page,omitempty"`
+	Limit      int        `json:"limit"`
+	HasNext    bool       `json:"has_next"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Links      PageLinks  `json:"links"`
+	Facets     *Facets    `json:"facets,omitempty"`
+	Backend    string     `json:"-"` // Which backend answered, see BackendElasticsearch, BackendMemory and BackendPostgres
+}
+
+// Facets summarise every article matching a search, not just the current page.
+type Facets struct {
+	Authors []FacetBucket `json:"authors,omitempty"` // Top authors by article count
+	Dates   []FacetBucket `json:"dates,omitempty"`   // Article count per interval, keyed by its first day (YYYY-MM-DD)
+}
+
+// FacetBucket is a single facet value with the number of matching articles.
+type FacetBucket struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// RelatedArticles are the articles most similar to a given one, most similar first.
+type RelatedArticles struct {
+	Data []*Article `json:"data"`
+}
+
+// Suggestions are the autocomplete matches for a partially typed search.
+type Suggestions struct {
+	Articles []ArticleSuggestion `json:"articles"`
+	Authors  []AuthorSuggestion  `json:"authors"`
+}
+
+// ArticleSuggestion is an article whose title matches the typed prefix.
+type ArticleSuggestion struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// AuthorSuggestion is an author whose name matches the typed prefix, with their number of articles.
+type AuthorSuggestion struct {
+	Name     string `json:"name"`
+	Articles int64  `json:"articles"`
+}
+
+// SavedSearch is a search query subscribed to, alerted on whenever a new article matches it.
+type SavedSearch struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Query     string    `json:"query"` // Same syntax as the query of article searches
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateSavedSearchRequest represents the request body for saving a search.
+type CreateSavedSearchRequest struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// SavedSearchList holds every saved search, newest first.
+type SavedSearchList struct {
+	Data []*SavedSearch `json:"data"`
+}
+
+// Alert tells that a newly posted article matches a saved search.
+type Alert struct {
+	SavedSearch *SavedSearch `json:"saved_search"`
+	Article     *Article     `json:"article"`
+}
+
+// PageLinks holds the URLs of the neighbouring pages, empty when there is none.
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
 }
@@ -0,0 +1,167 @@
+package article
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"kumparan-test/internal/author"
+)
+
+// Operators recognised in a search query, written as name:value.
+const (
+	queryOperatorAuthor = "author"
+	queryOperatorBefore = "before"
+	queryOperatorAfter  = "after"
+)
+
+// parsedQuery is a search query split into what to match and how to narrow the results.
+// For example `banjir "curah hujan" -bogor author:Bara after:2025-01-01`.
+type parsedQuery struct {
+	Words    []string  // Matched loosely, tolerating typos
+	Phrases  []string  // "quoted" text, matched as an exact phrase
+	Excluded []string  // -word or -"phrase", articles matching it are left out
+	Authors  []string  // author:name, any of them
+	Before   time.Time // before:YYYY-MM-DD, created before that day
+	After    time.Time // after:YYYY-MM-DD, created after that day
+}
+
+// parseQuery parses a search query. Values containing spaces are quoted, e.g. author:"Bara Ali".
+// Words that look like an unknown operator (e.g. a URL) are searched as they are.
+// It returns an error wrapping ErrInvalidQuery when the query is malformed.
+func parseQuery(q string) (*parsedQuery, error) {
+	query := &parsedQuery{}
+
+	for rest := strings.TrimSpace(q); rest != ""; rest = strings.TrimSpace(rest) {
+		excluded := strings.HasPrefix(rest, "-")
+		if excluded {
+			rest = rest[1:]
+		}
+
+		operator := ""
+		if !excluded {
+			for _, name := range []string{queryOperatorAuthor, queryOperatorBefore, queryOperatorAfter} {
+				if len(rest) > len(name) && strings.EqualFold(rest[:len(name)+1], name+":") {
+					operator = name
+					rest = rest[len(name)+1:]
+					break
+				}
+			}
+		}
+
+		value, quoted, remaining, err := nextQueryToken(rest)
+		if err != nil {
+			return nil, err
+		}
+		rest = remaining
+
+		switch {
+		case value == "" && operator != "":
+			return nil, fmt.Errorf("%w: missing value after %s:", ErrInvalidQuery, operator)
+		case value == "" && excluded && !quoted:
+			return nil, fmt.Errorf("%w: missing word after -", ErrInvalidQuery)
+		case value == "":
+			// An empty phrase matches nothing in particular
+		case operator == queryOperatorAuthor:
+			query.Authors = append(query.Authors, value)
+		case operator == queryOperatorBefore:
+			if query.Before, err = parseQueryDate(operator, value); err != nil {
+				return nil, err
+			}
+		case operator == queryOperatorAfter:
+			if query.After, err = parseQueryDate(operator, value); err != nil {
+				return nil, err
+			}
+		case excluded:
+			query.Excluded = append(query.Excluded, value)
+		case quoted:
+			query.Phrases = append(query.Phrases, value)
+		default:
+			query.Words = append(query.Words, value)
+		}
+	}
+
+	return query, nil
+}
+
+// nextQueryToken reads a quoted phrase or a single word from the start of s and returns it with the rest of s.
+func nextQueryToken(s string) (token string, quoted bool, rest string, err error) {
+	if strings.HasPrefix(s, `"`) {
+		end := strings.Index(s[1:], `"`)
+		if end < 0 {
+			return "", true, "", fmt.Errorf("%w: unterminated quote", ErrInvalidQuery)
+		}
+		return strings.TrimSpace(s[1 : end+1]), true, s[end+2:], nil
+	}
+
+	end := strings.IndexAny(s, " \t\r\n")
+	if end < 0 {
+		end = len(s)
+	}
+	// A stray quote inside a word is not a phrase
+	return strings.ReplaceAll(s[:end], `"`, ""), false, s[end:], nil
+}
+
+// parseQueryDate parses the YYYY-MM-DD value of a date operator.
+func parseQueryDate(operator, value string) (time.Time, error) {
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s: expects a date as YYYY-MM-DD", ErrInvalidQuery, operator)
+	}
+	return date, nil
+}
+
+// text returns the words, phrases and exclusions of the query in web search syntax,
+// as understood by PostgreSQL's websearch_to_tsquery. Operators are left out.
+func (q *parsedQuery) text() string {
+	parts := make([]string, 0, len(q.Words)+len(q.Phrases)+len(q.Excluded))
+	parts = append(parts, q.Words...)
+	for _, phrase := range q.Phrases {
+		parts = append(parts, `"`+phrase+`"`)
+	}
+	for _, excluded := range q.Excluded {
+		if strings.ContainsAny(excluded, " \t\r\n") {
+			excluded = `"` + excluded + `"`
+		}
+		parts = append(parts, "-"+excluded)
+	}
+	return strings.Join(parts, " ")
+}
+
+// narrow applies the operators of the query to filter, on top of the filters it already has,
+// and replaces its query with the remaining text. It returns false when no article can match both.
+func (q *parsedQuery) narrow(filter *ArticleFilter) bool {
+	filter.Query = q.text()
+
+	if len(q.Authors) > 0 {
+		if len(filter.Authors) == 0 {
+			filter.Authors = q.Authors
+		} else {
+			var both []string
+			for _, name := range filter.Authors {
+				for _, queried := range q.Authors {
+					if author.NameKey(name) == author.NameKey(queried) {
+						both = append(both, name)
+						break
+					}
+				}
+			}
+			if len(both) == 0 {
+				return false
+			}
+			filter.Authors = both
+		}
+	}
+
+	if !q.After.IsZero() {
+		// Created after that day means from the start of the next one
+		if from := q.After.AddDate(0, 0, 1); from.After(filter.From) {
+			filter.From = from
+		}
+	}
+	if !q.Before.IsZero() && (filter.To.IsZero() || q.Before.Before(filter.To)) {
+		filter.To = q.Before
+	}
+
+	return true
+}
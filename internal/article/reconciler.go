@@ -0,0 +1,276 @@
+package article
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+
+	"kumparan-test/pkg/search"
+
+	"github.com/sirupsen/logrus"
+)
+
+// reconcileMetrics exposes the outcome of the last reconciliation run on /metrics.
+var reconcileMetrics = expvar.NewMap("article_reconciliation")
+
+// ReconcileReport counts what a reconciliation run found and repaired.
+type ReconcileReport struct {
+	Checked  int // Articles found in PostgreSQL
+	Missing  int // Articles without a search document
+	Stale    int // Search documents that differ from their article
+	Orphaned int // Search documents without an article
+	Repaired int // Documents indexed or deleted
+	Failed   int // Documents that could not be repaired
+}
+
+// Reconciler detects and repairs drift between PostgreSQL and the Elasticsearch articles index.
+type Reconciler struct {
+	repo      Repository
+	esClient  search.SearchService
+	batchSize int
+}
+
+// NewReconciler creates a new Reconciler that compares articles batchSize at a time.
+func NewReconciler(repo Repository, esClient search.SearchService, batchSize int) *Reconciler {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &Reconciler{
+		repo:      repo,
+		esClient:  esClient,
+		batchSize: batchSize,
+	}
+}
+
+// RunEvery reconciles once per interval until ctx is cancelled.
+func (r *Reconciler) RunEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Run(ctx); err != nil && ctx.Err() == nil {
+				logrus.WithError(err).Error("Search index reconciliation failed")
+			}
+		}
+	}
+}
+
+// Run walks the articles table and the search index in ID order, re-indexing missing
+// or stale documents and deleting orphans. A document is stale when its checksum differs
+// from the one its article gives now, which also catches author renames and merges.
+func (r *Reconciler) Run(ctx context.Context) (*ReconcileReport, error) {
+	started := time.Now()
+	report := &ReconcileReport{}
+
+	stored := &versionIterator{fetch: func(afterID string) ([]*ArticleVersion, error) {
+		return r.storedVersions(ctx, afterID)
+	}}
+	indexed := &versionIterator{fetch: func(afterID string) ([]*ArticleVersion, error) {
+		return r.indexedVersions(ctx, afterID)
+	}}
+
+	var toIndex, orphans []string
+	for {
+		pg, err := stored.peek()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read articles: %w", err)
+		}
+		es, err := indexed.peek()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read search index: %w", err)
+		}
+		if pg == nil && es == nil {
+			break
+		}
+
+		switch {
+		case es == nil || (pg != nil && pg.ID < es.ID):
+			report.Checked++
+			report.Missing++
+			toIndex = append(toIndex, pg.ID)
+			stored.pop()
+		case pg == nil || es.ID < pg.ID:
+			report.Orphaned++
+			orphans = append(orphans, es.ID)
+			indexed.pop()
+		default:
+			report.Checked++
+			if pg.Checksum != es.Checksum {
+				report.Stale++
+				toIndex = append(toIndex, pg.ID)
+			}
+			stored.pop()
+			indexed.pop()
+		}
+
+		if len(toIndex) >= r.batchSize {
+			r.reindex(ctx, toIndex, report)
+			toIndex = nil
+		}
+	}
+	r.reindex(ctx, toIndex, report)
+	r.deleteOrphans(ctx, orphans, report)
+
+	r.publish(report, started)
+	logrus.WithFields(logrus.Fields{
+		"checked":  report.Checked,
+		"missing":  report.Missing,
+		"stale":    report.Stale,
+		"orphaned": report.Orphaned,
+		"repaired": report.Repaired,
+		"failed":   report.Failed,
+		"duration": time.Since(started).String(),
+	}).Info("Search index reconciliation finished")
+
+	return report, nil
+}
+
+// storedVersions reads the next page of articles after afterID, ordered by ID, and checksums
+// the search documents they make.
+func (r *Reconciler) storedVersions(ctx context.Context, afterID string) ([]*ArticleVersion, error) {
+	articles, err := r.repo.GetArticlesInIDOrder(ctx, afterID, r.batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*ArticleVersion, 0, len(articles))
+	for _, article := range articles {
+		versions = append(versions, &ArticleVersion{ID: article.ID, Checksum: newSearchDocument(article).Checksum})
+	}
+	return versions, nil
+}
+
+// indexedVersions reads the next page of search documents after afterID, ordered by ID.
+func (r *Reconciler) indexedVersions(ctx context.Context, afterID string) ([]*ArticleVersion, error) {
+	opts := search.SearchOptions{
+		Size:   r.batchSize,
+		Sort:   []search.SortField{{Field: search.ArticleFieldID, Ascending: true}},
+		Source: []string{search.ArticleFieldID, search.ArticleFieldChecksum},
+	}
+	if afterID != "" {
+		opts.SearchAfter = []interface{}{afterID}
+	}
+
+	result, err := r.esClient.SearchDocuments(ctx, search.ArticleIndexName, &search.MatchAllQuery{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*ArticleVersion, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		var doc search.ArticleDocument
+		if err := json.Unmarshal(hit.Source, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode search document %s: %w", hit.ID, err)
+		}
+		// Documents indexed before checksums existed have none and count as stale
+		versions = append(versions, &ArticleVersion{ID: hit.ID, Checksum: doc.Checksum})
+	}
+	return versions, nil
+}
+
+// reindex writes the current state of the given articles to the search index.
+func (r *Reconciler) reindex(ctx context.Context, ids []string, report *ReconcileReport) {
+	if len(ids) == 0 {
+		return
+	}
+
+	articles, err := r.repo.GetArticlesByID(ctx, ids)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to load articles for reconciliation")
+		report.Failed += len(ids)
+		return
+	}
+
+	docs := make(map[string]interface{}, len(articles))
+	for _, a := range articles {
+		docs[a.ID] = newSearchDocument(a)
+	}
+	if err := r.esClient.BulkIndexDocuments(ctx, search.ArticleIndexName, docs); err != nil {
+		report.Failed += len(docs)
+		return
+	}
+	report.Repaired += len(docs)
+}
+
+// deleteOrphans removes search documents whose article no longer exists.
+// Each orphan is checked again first, since it may belong to an article created during the run.
+func (r *Reconciler) deleteOrphans(ctx context.Context, ids []string, report *ReconcileReport) {
+	for _, id := range ids {
+		_, err := r.repo.GetArticleByID(ctx, id)
+		if err == nil {
+			report.Orphaned--
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			logrus.WithError(err).WithField("article_id", id).Error("Failed to check orphaned search document")
+			report.Failed++
+			continue
+		}
+
+		if err := r.esClient.DeleteDocument(ctx, search.ArticleIndexName, id); err != nil {
+			report.Failed++
+			continue
+		}
+		report.Repaired++
+	}
+}
+
+// publish records the report in the expvar metrics.
+func (r *Reconciler) publish(report *ReconcileReport, started time.Time) {
+	set := func(key string, value int64) {
+		v := new(expvar.Int)
+		v.Set(value)
+		reconcileMetrics.Set(key, v)
+	}
+
+	reconcileMetrics.Add("runs", 1)
+	set("last_run_unix", started.Unix())
+	set("last_duration_ms", time.Since(started).Milliseconds())
+	set("last_checked", int64(report.Checked))
+	set("last_missing", int64(report.Missing))
+	set("last_stale", int64(report.Stale))
+	set("last_orphaned", int64(report.Orphaned))
+	set("last_repaired", int64(report.Repaired))
+	set("last_failed", int64(report.Failed))
+}
+
+// versionIterator walks an ID-ordered source of article versions one batch at a time.
+type versionIterator struct {
+	fetch  func(afterID string) ([]*ArticleVersion, error)
+	buf    []*ArticleVersion
+	lastID string
+	done   bool
+}
+
+// peek returns the next version without consuming it, or nil when the source is exhausted.
+func (it *versionIterator) peek() (*ArticleVersion, error) {
+	if len(it.buf) == 0 && !it.done {
+		batch, err := it.fetch(it.lastID)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			it.done = true
+		} else {
+			it.buf = batch
+			it.lastID = batch[len(batch)-1].ID
+		}
+	}
+	if len(it.buf) == 0 {
+		return nil, nil
+	}
+	return it.buf[0], nil
+}
+
+// pop consumes the version returned by peek.
+func (it *versionIterator) pop() {
+	it.buf = it.buf[1:]
+}
@@ -0,0 +1,171 @@
+package article_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"kumparan-test/internal/article"
+	"kumparan-test/internal/article/mocks"
+	"kumparan-test/internal/author"
+	"kumparan-test/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// indexedHit returns the search hit of an article as the indexer stores it.
+func indexedHit(t *testing.T, a *article.Article) *search.Hit {
+	doc := &search.ArticleDocument{
+		ID: a.ID, Title: a.Title, Body: a.Body, Authors: []string{}, AuthorIDs: []string{}, AuthorKeys: []string{},
+		CreatedAt: a.CreatedAt, UpdatedAt: a.UpdatedAt,
+	}
+	for _, byline := range a.Authors {
+		doc.Authors = append(doc.Authors, byline.Name)
+		doc.AuthorIDs = append(doc.AuthorIDs, byline.ID)
+		doc.AuthorKeys = append(doc.AuthorKeys, author.NameKey(byline.Name))
+	}
+	doc.Checksum = doc.ContentChecksum()
+
+	source, err := json.Marshal(doc)
+	assert.NoError(t, err)
+	return &search.Hit{ID: a.ID, Source: source}
+}
+
+func searchResult(hits ...*search.Hit) *search.SearchResult {
+	return &search.SearchResult{Total: int64(len(hits)), Hits: hits}
+}
+
+// onIndexedPages serves the given hits as the first page of the articles index and nothing after it.
+func onIndexedPages(mockSearch *mocks.MockSearchService, hits ...*search.Hit) {
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return opts.SearchAfter == nil
+	})).Return(searchResult(hits...), nil)
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.Anything).
+		Return(searchResult(), nil)
+}
+
+func TestReconciler_RepairsMissingStaleAndOrphaned(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	reconciler := article.NewReconciler(mockRepo, mockSearch, 10)
+
+	now := time.Now()
+	a1 := &article.Article{ID: "a1", Title: "Banjir", UpdatedAt: now}
+	a2 := &article.Article{ID: "a2", Title: "Macet", UpdatedAt: now}
+	a4 := &article.Article{ID: "a4", Title: "Pemilu", UpdatedAt: now}
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "", 10).Return([]*article.Article{a1, a2, a4}, nil)
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "a4", 10).Return([]*article.Article{}, nil)
+
+	onIndexedPages(mockSearch,
+		indexedHit(t, a1),
+		indexedHit(t, &article.Article{ID: "a2", Title: "Macet di Bogor", UpdatedAt: now.Add(-time.Hour)}),
+		indexedHit(t, &article.Article{ID: "a3"}),
+		indexedHit(t, &article.Article{ID: "a5"}),
+	)
+
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"a2", "a4"}).Return([]*article.Article{a2, a4}, nil)
+	mockSearch.On("BulkIndexDocuments", mock.Anything, search.ArticleIndexName, mock.MatchedBy(func(docs map[string]interface{}) bool {
+		return len(docs) == 2 && docs["a2"] != nil && docs["a4"] != nil
+	})).Return(nil)
+
+	// a3 is really gone, a5 was created while the run was in progress
+	mockRepo.On("GetArticleByID", mock.Anything, "a3").Return(nil, sql.ErrNoRows)
+	mockRepo.On("GetArticleByID", mock.Anything, "a5").Return(&article.Article{ID: "a5"}, nil)
+	mockSearch.On("DeleteDocument", mock.Anything, search.ArticleIndexName, "a3").Return(nil)
+
+	report, err := reconciler.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &article.ReconcileReport{Checked: 3, Missing: 1, Stale: 1, Orphaned: 1, Repaired: 3}, report)
+	mockRepo.AssertExpectations(t)
+	mockSearch.AssertExpectations(t)
+	mockSearch.AssertNotCalled(t, "DeleteDocument", mock.Anything, search.ArticleIndexName, "a5")
+}
+
+func TestReconciler_InSync(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	reconciler := article.NewReconciler(mockRepo, mockSearch, 10)
+
+	bara := author.Author{ID: "auth-1", Name: "Bara"}
+	a1 := &article.Article{ID: "a1", Title: "Banjir", Author: bara, Authors: article.Bylines{{Author: bara, Role: article.RoleWriter}},
+		UpdatedAt: time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC)}
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "", 10).Return([]*article.Article{a1}, nil)
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "a1", 10).Return([]*article.Article{}, nil)
+	onIndexedPages(mockSearch, indexedHit(t, a1))
+
+	report, err := reconciler.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &article.ReconcileReport{Checked: 1}, report)
+	mockSearch.AssertNotCalled(t, "BulkIndexDocuments", mock.Anything, mock.Anything, mock.Anything)
+	mockSearch.AssertNotCalled(t, "DeleteDocument", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReconciler_AuthorRenameIsStale(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	reconciler := article.NewReconciler(mockRepo, mockSearch, 10)
+
+	// The author was renamed without touching the article row, and the outbox event was lost
+	updatedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	renamed := author.Author{ID: "auth-1", Name: "Bara Ali"}
+	a1 := &article.Article{ID: "a1", Title: "Banjir", Author: renamed, Authors: article.Bylines{{Author: renamed, Role: article.RoleWriter}}, UpdatedAt: updatedAt}
+	indexed := author.Author{ID: "auth-1", Name: "Bara"}
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "", 10).Return([]*article.Article{a1}, nil)
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "a1", 10).Return([]*article.Article{}, nil)
+	onIndexedPages(mockSearch, indexedHit(t, &article.Article{ID: "a1", Title: "Banjir", Author: indexed,
+		Authors: article.Bylines{{Author: indexed, Role: article.RoleWriter}}, UpdatedAt: updatedAt}))
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"a1"}).Return([]*article.Article{a1}, nil)
+	mockSearch.On("BulkIndexDocuments", mock.Anything, search.ArticleIndexName, mock.MatchedBy(func(docs map[string]interface{}) bool {
+		doc, ok := docs["a1"].(*search.ArticleDocument)
+		return ok && assert.ObjectsAreEqual([]string{"Bara Ali"}, doc.Authors)
+	})).Return(nil)
+
+	report, err := reconciler.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &article.ReconcileReport{Checked: 1, Stale: 1, Repaired: 1}, report)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestReconciler_DocumentsWithoutChecksumAreStale(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	reconciler := article.NewReconciler(mockRepo, mockSearch, 10)
+
+	// Indexed before checksums were added to the mapping
+	updatedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	source, err := json.Marshal(map[string]interface{}{"id": "a1", "updated_at": updatedAt})
+	assert.NoError(t, err)
+
+	a1 := &article.Article{ID: "a1", UpdatedAt: updatedAt}
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "", 10).Return([]*article.Article{a1}, nil)
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "a1", 10).Return([]*article.Article{}, nil)
+	onIndexedPages(mockSearch, &search.Hit{ID: "a1", Source: source})
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"a1"}).Return([]*article.Article{a1}, nil)
+	mockSearch.On("BulkIndexDocuments", mock.Anything, search.ArticleIndexName, mock.Anything).Return(nil)
+
+	report, err := reconciler.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &article.ReconcileReport{Checked: 1, Stale: 1, Repaired: 1}, report)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestReconciler_SearchErrorAborts(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	reconciler := article.NewReconciler(mockRepo, mockSearch, 10)
+
+	mockRepo.On("GetArticlesInIDOrder", mock.Anything, "", 10).Return([]*article.Article{}, nil)
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.Anything).
+		Return((*search.SearchResult)(nil), assert.AnError)
+
+	_, err := reconciler.Run(context.Background())
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
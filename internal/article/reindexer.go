@@ -0,0 +1,165 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kumparan-test/pkg/search"
+
+	"github.com/sirupsen/logrus"
+)
+
+// catchUpMargin widens the catch-up window to cover writes committed around the start of the copy
+// and clock differences between the service and the database.
+const catchUpMargin = time.Minute
+
+// Reindexer rebuilds the Elasticsearch articles index from PostgreSQL without downtime.
+// Searches keep using the current index until the new one is complete and the alias is switched.
+// Meanwhile the outbox worker keeps writing to the current index, so the changes made during
+// the copy are replayed on the new index once the alias points at it.
+type Reindexer struct {
+	repo      Repository
+	esClient  search.SearchService
+	batchSize int
+}
+
+// NewReindexer creates a new Reindexer that reads articles batchSize at a time.
+func NewReindexer(repo Repository, esClient search.SearchService, batchSize int) *Reindexer {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &Reindexer{
+		repo:      repo,
+		esClient:  esClient,
+		batchSize: batchSize,
+	}
+}
+
+// Run builds a new versioned index, switches the articles alias to it, catches up with the
+// articles changed since the copy started and, when deleteOld is set, deletes the indices the
+// alias pointed at before. It returns the new index name.
+func (r *Reindexer) Run(ctx context.Context, deleteOld bool) (string, error) {
+	started := time.Now()
+	indexName := search.VersionedIndexName(search.ArticleIndexName, search.ArticleMappingVersion)
+	exists, err := r.esClient.IndexExists(ctx, indexName)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		// Rebuilding the same mapping version, give the new copy its own name
+		indexName = fmt.Sprintf("%s_%d", indexName, time.Now().Unix())
+	}
+
+	if err := r.esClient.CreateIndex(ctx, indexName, search.ArticleMapping); err != nil {
+		return "", err
+	}
+
+	total, err := r.copyArticles(ctx, indexName)
+	if err != nil {
+		// The half-built index is not served by the alias yet, so it is safe to drop
+		if delErr := r.esClient.DeleteIndex(ctx, indexName); delErr != nil {
+			logrus.WithError(delErr).WithField("index", indexName).Warn("Failed to clean up partial index")
+		}
+		return "", err
+	}
+
+	previous, err := r.esClient.SwitchAlias(ctx, search.ArticleIndexName, indexName)
+	if err != nil {
+		return "", err
+	}
+
+	// From here on the outbox worker writes to the new index, which still misses what it wrote
+	// to the old one during the copy
+	changed, deleted, err := r.catchUp(ctx, indexName, started.Add(-catchUpMargin))
+	if err != nil {
+		return indexName, fmt.Errorf("failed to catch up with changes made during the reindex: %w", err)
+	}
+	logrus.WithFields(logrus.Fields{
+		"index":    indexName,
+		"articles": total,
+		"changed":  changed,
+		"deleted":  deleted,
+	}).Info("Articles reindexed")
+
+	if deleteOld {
+		for _, old := range previous {
+			if err := r.esClient.DeleteIndex(ctx, old); err != nil {
+				return indexName, err
+			}
+		}
+	}
+
+	return indexName, nil
+}
+
+// copyArticles bulk indexes every article into indexName, oldest first, and returns how many were copied.
+func (r *Reindexer) copyArticles(ctx context.Context, indexName string) (int, error) {
+	filter := &ArticleFilter{Limit: r.batchSize, Sort: SortOldest}
+	var cursor *Cursor
+	total := 0
+
+	for {
+		articles, hasNext, err := r.repo.GetArticlesAfter(ctx, filter, cursor)
+		if err != nil {
+			return total, fmt.Errorf("failed to read articles: %w", err)
+		}
+
+		docs := make(map[string]interface{}, len(articles))
+		for _, a := range articles {
+			docs[a.ID] = newSearchDocument(a)
+		}
+		if err := r.esClient.BulkIndexDocuments(ctx, indexName, docs); err != nil {
+			return total, err
+		}
+
+		total += len(articles)
+		logrus.WithFields(logrus.Fields{"index": indexName, "articles": total}).Info("Reindex batch written")
+
+		if !hasNext || len(articles) == 0 {
+			return total, nil
+		}
+		last := articles[len(articles)-1]
+		cursor = &Cursor{Sort: SortOldest, CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// catchUp copies the articles changed since the given time into indexName again and deletes the
+// articles removed since then, returning how many of each it wrote.
+func (r *Reindexer) catchUp(ctx context.Context, indexName string, since time.Time) (int, int, error) {
+	deletedIDs, err := r.repo.GetDeletedArticleIDs(ctx, since)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read deleted articles: %w", err)
+	}
+	for _, id := range deletedIDs {
+		if err := r.esClient.DeleteDocument(ctx, indexName, id); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	changed := 0
+	afterID := ""
+	for {
+		articles, err := r.repo.GetArticlesChangedSince(ctx, since, afterID, r.batchSize)
+		if err != nil {
+			return changed, len(deletedIDs), fmt.Errorf("failed to read changed articles: %w", err)
+		}
+		if len(articles) == 0 {
+			return changed, len(deletedIDs), nil
+		}
+
+		docs := make(map[string]interface{}, len(articles))
+		for _, a := range articles {
+			docs[a.ID] = newSearchDocument(a)
+		}
+		if err := r.esClient.BulkIndexDocuments(ctx, indexName, docs); err != nil {
+			return changed, len(deletedIDs), err
+		}
+
+		changed += len(articles)
+		if len(articles) < r.batchSize {
+			return changed, len(deletedIDs), nil
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+}
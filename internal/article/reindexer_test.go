@@ -0,0 +1,155 @@
+package article_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"kumparan-test/internal/article"
+	"kumparan-test/internal/article/mocks"
+	"kumparan-test/internal/author"
+	"kumparan-test/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// currentIndex is the index a reindex of the current mapping version writes to.
+var currentIndex = search.VersionedIndexName(search.ArticleIndexName, search.ArticleMappingVersion)
+
+func TestReindexer_CopiesInBatchesAndSwitchesAlias(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	reindexer := article.NewReindexer(mockRepo, mockSearch, 2)
+
+	createdAt := time.Now()
+	bara := author.Author{ID: "auth-1", Name: "Bara"}
+	sari := author.Author{ID: "auth-2", Name: "Sari"}
+	first := []*article.Article{
+		{ID: "a1", Title: "T1", Author: bara, Authors: article.Bylines{{Author: bara, Role: article.RoleWriter}}, CreatedAt: createdAt},
+		{ID: "a2", Title: "T2", Author: bara, Authors: article.Bylines{{Author: bara, Role: article.RoleWriter}}, CreatedAt: createdAt},
+	}
+	second := []*article.Article{
+		{ID: "a3", Title: "T3", Author: sari, Authors: article.Bylines{{Author: sari, Role: article.RoleWriter}}, CreatedAt: createdAt},
+	}
+	filter := &article.ArticleFilter{Limit: 2, Sort: article.SortOldest}
+
+	mockSearch.On("IndexExists", mock.Anything, currentIndex).Return(false, nil)
+	mockSearch.On("CreateIndex", mock.Anything, currentIndex, search.ArticleMapping).Return(nil)
+	mockRepo.On("GetArticlesAfter", mock.Anything, filter, (*article.Cursor)(nil)).Return(first, true, nil)
+	mockRepo.On("GetArticlesAfter", mock.Anything, filter, mock.MatchedBy(func(c *article.Cursor) bool {
+		return c != nil && c.ID == "a2"
+	})).Return(second, false, nil)
+	mockSearch.On("BulkIndexDocuments", mock.Anything, currentIndex, mock.MatchedBy(func(docs map[string]interface{}) bool {
+		return len(docs) == 2 && docs["a1"].(*search.ArticleDocument).AuthorIDs[0] == "auth-1"
+	})).Return(nil)
+	mockSearch.On("BulkIndexDocuments", mock.Anything, currentIndex, mock.MatchedBy(func(docs map[string]interface{}) bool {
+		return len(docs) == 1 && docs["a3"].(*search.ArticleDocument).Authors[0] == "Sari"
+	})).Return(nil)
+	mockSearch.On("SwitchAlias", mock.Anything, search.ArticleIndexName, currentIndex).Return([]string{"articles_v1"}, nil)
+	mockRepo.On("GetDeletedArticleIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
+	mockRepo.On("GetArticlesChangedSince", mock.Anything, mock.Anything, "", 2).Return([]*article.Article{}, nil)
+	mockSearch.On("DeleteIndex", mock.Anything, "articles_v1").Return(nil)
+
+	indexName, err := reindexer.Run(context.Background(), true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, currentIndex, indexName)
+	mockRepo.AssertExpectations(t)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestReindexer_ExistingVersionGetsNewName(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	reindexer := article.NewReindexer(mockRepo, mockSearch, 10)
+
+	isRebuild := mock.MatchedBy(func(name string) bool { return strings.HasPrefix(name, currentIndex+"_") })
+	mockSearch.On("IndexExists", mock.Anything, currentIndex).Return(true, nil)
+	mockSearch.On("CreateIndex", mock.Anything, isRebuild, search.ArticleMapping).Return(nil)
+	mockRepo.On("GetArticlesAfter", mock.Anything, mock.Anything, (*article.Cursor)(nil)).Return([]*article.Article{}, false, nil)
+	mockSearch.On("BulkIndexDocuments", mock.Anything, isRebuild, map[string]interface{}{}).Return(nil)
+	mockSearch.On("SwitchAlias", mock.Anything, search.ArticleIndexName, isRebuild).Return([]string{currentIndex}, nil)
+	mockRepo.On("GetDeletedArticleIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
+	mockRepo.On("GetArticlesChangedSince", mock.Anything, mock.Anything, "", 10).Return([]*article.Article{}, nil)
+
+	indexName, err := reindexer.Run(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(indexName, currentIndex+"_"))
+	mockSearch.AssertNotCalled(t, "DeleteIndex", mock.Anything, mock.Anything)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestReindexer_BulkFailureDropsPartialIndex(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+	reindexer := article.NewReindexer(mockRepo, mockSearch, 10)
+
+	mockSearch.On("IndexExists", mock.Anything, currentIndex).Return(false, nil)
+	mockSearch.On("CreateIndex", mock.Anything, currentIndex, search.ArticleMapping).Return(nil)
+	mockRepo.On("GetArticlesAfter", mock.Anything, mock.Anything, (*article.Cursor)(nil)).
+		Return([]*article.Article{{ID: "a1"}}, false, nil)
+	mockSearch.On("BulkIndexDocuments", mock.Anything, currentIndex, mock.Anything).Return(assert.AnError)
+	mockSearch.On("DeleteIndex", mock.Anything, currentIndex).Return(nil)
+
+	_, err := reindexer.Run(context.Background(), true)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	mockSearch.AssertNotCalled(t, "SwitchAlias", mock.Anything, mock.Anything, mock.Anything)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestReindexer_CatchesUpWithChangesDuringCopy(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.MockRepo)
+	searchService := search.NewMemorySearchService()
+	reindexer := article.NewReindexer(mockRepo, searchService, 10)
+
+	// The alias serves an older index, which the outbox worker keeps writing to
+	assert.NoError(t, searchService.CreateIndex(ctx, "articles_v1", search.ArticleMapping))
+	_, err := searchService.SwitchAlias(ctx, search.ArticleIndexName, "articles_v1")
+	assert.NoError(t, err)
+
+	bara := author.Author{ID: "auth-1", Name: "Bara"}
+	byline := article.Bylines{{Author: bara, Role: article.RoleWriter}}
+	createdAt := time.Now().Add(-time.Hour)
+	before := []*article.Article{
+		{ID: "a1", Title: "Old title", Author: bara, Authors: byline, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: "a2", Title: "Soon deleted", Author: bara, Authors: byline, CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+	after := []*article.Article{
+		{ID: "a1", Title: "New title", Author: bara, Authors: byline, CreatedAt: createdAt, UpdatedAt: time.Now()},
+		{ID: "a3", Title: "Brand new", Author: bara, Authors: byline, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	started := time.Now()
+	mockRepo.On("GetArticlesAfter", mock.Anything, mock.Anything, (*article.Cursor)(nil)).
+		Run(func(mock.Arguments) {
+			// a1 is edited, a2 deleted and a3 created after the copy read them
+			for _, a := range after {
+				assert.NoError(t, searchService.IndexDocument(ctx, search.ArticleIndexName, a.ID, &search.ArticleDocument{ID: a.ID, Title: a.Title}))
+			}
+			assert.NoError(t, searchService.DeleteDocument(ctx, search.ArticleIndexName, "a2"))
+		}).
+		Return(before, false, nil)
+	sinceCopyStarted := mock.MatchedBy(func(since time.Time) bool { return !since.After(started) })
+	mockRepo.On("GetDeletedArticleIDs", mock.Anything, sinceCopyStarted).Return([]string{"a2"}, nil)
+	mockRepo.On("GetArticlesChangedSince", mock.Anything, sinceCopyStarted, "", 10).Return(after, nil)
+
+	_, err = reindexer.Run(ctx, true)
+	assert.NoError(t, err)
+
+	result, err := searchService.SearchDocuments(ctx, search.ArticleIndexName, &search.MatchAllQuery{}, search.SearchOptions{Size: 10})
+	assert.NoError(t, err)
+	titles := map[string]string{}
+	for _, hit := range result.Hits {
+		var doc search.ArticleDocument
+		assert.NoError(t, json.Unmarshal(hit.Source, &doc))
+		titles[hit.ID] = doc.Title
+	}
+	assert.Equal(t, map[string]string{"a1": "New title", "a3": "Brand new"}, titles)
+	mockRepo.AssertExpectations(t)
+}
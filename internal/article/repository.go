@@ -4,16 +4,40 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
+
+	"kumparan-test/internal/outbox"
 
 	"github.com/lib/pq"
 )
 
 type Repository interface {
 	CreateArticle(ctx context.Context, article *Article) (*Article, error)
-	GetArticles(ctx context.Context, filter *ArticleFilter) ([]*Article, error)
-	GetArticlesByID(ctx context.Context, filter *ArticleFilter, ids []string) ([]*Article, error) // For fetching full articles from ES IDs
+	GetArticles(ctx context.Context, filter *ArticleFilter) ([]*Article, int64, error)
+	GetArticlesAfter(ctx context.Context, filter *ArticleFilter, cursor *Cursor) ([]*Article, bool, error)
+	CountArticles(ctx context.Context, filter *ArticleFilter) (int64, error)
+	GetArticlesInIDOrder(ctx context.Context, afterID string, limit int) ([]*Article, error)
+	GetArticlesChangedSince(ctx context.Context, since time.Time, afterID string, limit int) ([]*Article, error)
+	GetDeletedArticleIDs(ctx context.Context, since time.Time) ([]string, error)
+	GetArticlesByID(ctx context.Context, ids []string) ([]*Article, error) // For fetching full articles from ES IDs
+	GetArticleByID(ctx context.Context, id string) (*Article, error)
+	UpdateArticle(ctx context.Context, article *Article) (*Article, error)
+	DeleteArticle(ctx context.Context, id string) error
 }
 
+// articleColumns are the columns selected for every article, with the profile of its lead author and its bylines,
+// in the order of articleFields.
+const articleColumns = "a.id, a.title, a.body, a.created_at, a.updated_at, authors.id, authors.name, authors.slug, authors.bio, " +
+	"authors.avatar_url, authors.social_handles, authors.created_at, authors.updated_at, " + bylinesColumn
+
+// bylinesColumn aggregates the bylines of an article, with the profiles of their authors, into a JSON array in
+// credit order. Timestamps are converted to timestamptz so that their JSON carries a time zone.
+const bylinesColumn = `(SELECT json_agg(json_build_object('id', au.id, 'name', au.name, 'slug', au.slug, 'bio', au.bio, ` +
+	`'avatar_url', au.avatar_url, 'social_handles', au.social_handles, 'created_at', au.created_at AT TIME ZONE 'UTC', ` +
+	`'updated_at', au.updated_at AT TIME ZONE 'UTC', 'role', aa.role) ORDER BY aa.position) ` +
+	`FROM article_authors aa JOIN authors au ON au.id = aa.author_id WHERE aa.article_id = a.id)`
+
 type postgresRepository struct {
 	db *sql.DB
 }
@@ -23,52 +47,221 @@ func NewPostgresRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-// CreateArticle inserts a new article into the database.
+// articleFields returns the scan destinations of articleColumns.
+func articleFields(article *Article) []interface{} {
+	return []interface{}{
+		&article.ID, &article.Title, &article.Body, &article.CreatedAt, &article.UpdatedAt,
+		&article.Author.ID, &article.Author.Name, &article.Author.Slug, &article.Author.Bio,
+		&article.Author.AvatarURL, &article.Author.SocialHandles, &article.Author.CreatedAt, &article.Author.UpdatedAt,
+		&article.Authors,
+	}
+}
+
+// CreateArticle inserts a new article into the database with its bylines, together with its indexing outbox event.
+// AuthorID must be the author of the first byline.
 func (r *postgresRepository) CreateArticle(ctx context.Context, article *Article) (*Article, error) {
-	query := `INSERT INTO articles (title, body, author_id, created_at) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
-	err := r.db.QueryRow(query, article.Title, article.Body, article.AuthorID, article.CreatedAt).Scan(&article.ID, &article.CreatedAt)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO articles (title, body, author_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $4) RETURNING id, created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query, article.Title, article.Body, article.AuthorID, article.CreatedAt).Scan(&article.ID, &article.CreatedAt, &article.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := insertBylines(ctx, tx, article); err != nil {
+		return nil, err
+	}
+
+	if err := outbox.Enqueue(ctx, tx, &outbox.Event{AggregateID: article.ID, EventType: EventArticleCreated}); err != nil {
+		return nil, fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return article, nil
 }
 
-// GetArticles retrieves a list of articles from the database based on filters.
-// This method is used when no full-text search query is provided.
-func (r *postgresRepository) GetArticles(ctx context.Context, filter *ArticleFilter) ([]*Article, error) {
+// GetArticles retrieves a list of articles from the database based on filters,
+// along with the total number of articles matching those filters.
+// It serves listings without a query, and searches with a query while Elasticsearch is unavailable.
+func (r *postgresRepository) GetArticles(ctx context.Context, filter *ArticleFilter) ([]*Article, int64, error) {
 	articles := []*Article{}
+	var total int64
 	var err error
 
-	// Base query
-	query := "SELECT a.id, a.title, a.body, authors.id, authors.name, a.created_at FROM articles a "
+	// Base query, the window function counts every matching row before LIMIT is applied
+	query := "SELECT " + articleColumns + ", COUNT(*) OVER() FROM articles a "
 	query += "JOIN authors ON a.author_id = authors.id"
-	args := []interface{}{}
-	argCount := 1
+	where, args := buildArticleConditions(filter)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	argCount := len(args) + 1
 
-	// Add author filter if present
-	if filter.Author != "" {
-		query += fmt.Sprintf(" WHERE authors.name = $%d", argCount)
-		args = append(args, filter.Author)
+	// Order by creation time, the id breaks ties so pages never overlap
+	direction := sortDirection(filter)
+	query += " ORDER BY "
+	if filter.Query != "" && filter.Sort == SortRelevance {
+		query += fmt.Sprintf("ts_rank(a.search_vector, websearch_to_tsquery('simple', $%d)) DESC, ", argCount)
+		args = append(args, filter.Query)
 		argCount++
 	}
-
-	// Order by latest first
-	query += " ORDER BY created_at DESC"
+	query += fmt.Sprintf("a.created_at %s, a.id %s", direction, direction)
 
 	// Add pagination
 	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCount, argCount+1)
 	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
 
 	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article Article
+		if err := rows.Scan(append(articleFields(&article), &total)...); err != nil {
+			return nil, 0, err
+		}
+		articles = append(articles, &article)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+
+	// A page past the end returns no rows to carry the window count, so count separately
+	if len(articles) == 0 && filter.Page > 1 {
+		total, err = r.CountArticles(ctx, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return articles, total, nil
+}
+
+// GetArticlesAfter retrieves the page of articles that follows the cursor position,
+// reporting whether more articles exist after it.
+// Unlike GetArticles, the cost does not grow with how deep the page is.
+func (r *postgresRepository) GetArticlesAfter(ctx context.Context, filter *ArticleFilter, cursor *Cursor) ([]*Article, bool, error) {
+	articles := []*Article{}
+
+	query := "SELECT " + articleColumns + " FROM articles a "
+	query += "JOIN authors ON a.author_id = authors.id"
+	where, args := buildArticleConditions(filter)
+	argCount := len(args) + 1
+	direction := sortDirection(filter)
+
+	if cursor != nil {
+		comparison := "<"
+		if direction == "ASC" {
+			comparison = ">"
+		}
+		where = append(where, fmt.Sprintf("(a.created_at, a.id) %s ($%d, $%d)", comparison, argCount, argCount+1))
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		argCount += 2
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	// Matches idx_articles_created_at_id (scanned backwards for ASC); one extra row tells whether there is a next page
+	query += fmt.Sprintf(" ORDER BY a.created_at %s, a.id %s", direction, direction)
+	query += fmt.Sprintf(" LIMIT $%d", argCount)
+	args = append(args, filter.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article Article
+		if err := rows.Scan(articleFields(&article)...); err != nil {
+			return nil, false, err
+		}
+		articles = append(articles, &article)
+	}
+
+	if rows.Err() != nil {
+		return nil, false, rows.Err()
+	}
+
+	hasNext := len(articles) > filter.Limit
+	if hasNext {
+		articles = articles[:filter.Limit]
+	}
+
+	return articles, hasNext, nil
+}
+
+// CountArticles counts the articles matching the filters, ignoring pagination.
+func (r *postgresRepository) CountArticles(ctx context.Context, filter *ArticleFilter) (int64, error) {
+	query := "SELECT COUNT(*) FROM articles a JOIN authors ON a.author_id = authors.id"
+	where, args := buildArticleConditions(filter)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetArticlesInIDOrder retrieves up to limit articles ordered by ID, starting after afterID
+// (from the first article when empty).
+func (r *postgresRepository) GetArticlesInIDOrder(ctx context.Context, afterID string, limit int) ([]*Article, error) {
+	query := "SELECT " + articleColumns + " FROM articles a JOIN authors ON a.author_id = authors.id"
+	args := []interface{}{}
+	if afterID != "" {
+		args = append(args, afterID)
+		query += " WHERE a.id > $1"
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY a.id LIMIT $%d", len(args))
+
+	return r.queryArticles(ctx, query, args...)
+}
+
+// GetArticlesChangedSince retrieves up to limit articles updated at or after since, or crediting an author
+// updated since then, ordered by ID and starting after afterID (from the first article when empty).
+func (r *postgresRepository) GetArticlesChangedSince(ctx context.Context, since time.Time, afterID string, limit int) ([]*Article, error) {
+	query := "SELECT " + articleColumns + " FROM articles a JOIN authors ON a.author_id = authors.id"
+	query += " WHERE (a.updated_at >= $1 OR EXISTS (SELECT 1 FROM article_authors aa JOIN authors au ON au.id = aa.author_id " +
+		"WHERE aa.article_id = a.id AND au.updated_at >= $1))"
+	args := []interface{}{since}
+	if afterID != "" {
+		args = append(args, afterID)
+		query += fmt.Sprintf(" AND a.id > $%d", len(args))
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY a.id LIMIT $%d", len(args))
+
+	return r.queryArticles(ctx, query, args...)
+}
+
+// queryArticles runs a query selecting articleColumns and returns the articles it found.
+func (r *postgresRepository) queryArticles(ctx context.Context, query string, args ...interface{}) ([]*Article, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	articles := []*Article{}
 	for rows.Next() {
 		var article Article
-		if err := rows.Scan(&article.ID, &article.Title, &article.Body, &article.Author.ID, &article.Author.Name, &article.CreatedAt); err != nil {
+		if err := rows.Scan(articleFields(&article)...); err != nil {
 			return nil, err
 		}
 		articles = append(articles, &article)
@@ -81,26 +274,88 @@ func (r *postgresRepository) GetArticles(ctx context.Context, filter *ArticleFil
 	return articles, nil
 }
 
-// GetArticlesByID retrieves articles by their IDs. Used after an Elasticsearch search.
-func (r *postgresRepository) GetArticlesByID(ctx context.Context, filter *ArticleFilter, ids []string) ([]*Article, error) {
+// GetDeletedArticleIDs retrieves the IDs of the articles deleted at or after since, read from their outbox events.
+func (r *postgresRepository) GetDeletedArticleIDs(ctx context.Context, since time.Time) ([]string, error) {
+	query := `SELECT DISTINCT aggregate_id FROM outbox_events WHERE event_type = $1 AND created_at >= $2`
+	rows, err := r.db.QueryContext(ctx, query, EventArticleDeleted, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return ids, nil
+}
+
+// buildArticleConditions translates the listing filters into WHERE conditions
+// and their positional arguments, numbered from $1.
+func buildArticleConditions(filter *ArticleFilter) ([]string, []interface{}) {
+	where := []string{}
+	args := []interface{}{}
+
+	// Authors match any byline, not only the lead author, and names match like author lookups do
+	if len(filter.Authors) > 0 {
+		args = append(args, pq.Array(nameKeys(filter.Authors)))
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM article_authors aa JOIN authors au ON au.id = aa.author_id "+
+			"WHERE aa.article_id = a.id AND au.name_key = ANY($%d))", len(args)))
+	}
+	if len(filter.AuthorIDs) > 0 {
+		args = append(args, pq.Array(filter.AuthorIDs))
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM article_authors aa WHERE aa.article_id = a.id AND aa.author_id = ANY($%d))", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		where = append(where, fmt.Sprintf("a.created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		where = append(where, fmt.Sprintf("a.created_at < $%d", len(args)))
+	}
+
+	// Full-text search, normally served by Elasticsearch
+	if filter.Query != "" {
+		args = append(args, filter.Query)
+		where = append(where, fmt.Sprintf("a.search_vector @@ websearch_to_tsquery('simple', $%d)", len(args)))
+	}
+
+	return where, args
+}
+
+// sortDirection returns the SQL direction of the creation time ordering requested by the filter.
+func sortDirection(filter *ArticleFilter) string {
+	if filter.Sort == SortOldest {
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// GetArticlesByID retrieves articles by their IDs, in no particular order.
+// Used after an Elasticsearch search, where the caller restores the order of the hits.
+// The search already applied the listing filters, so they are not repeated here.
+func (r *postgresRepository) GetArticlesByID(ctx context.Context, ids []string) ([]*Article, error) {
 	if len(ids) == 0 {
 		return []*Article{}, nil
 	}
 
 	articles := []*Article{}
-	var args []interface{}
-	args = append(args, pq.Array(ids))
 
-	query := `SELECT a.id, a.title, a.body, a.created_at, authors.id, authors.name FROM articles a `
+	query := `SELECT ` + articleColumns + ` FROM articles a `
 	query += `JOIN authors ON a.author_id = authors.id `
-	query += `WHERE a.id = ANY($1) `
-	if filter != nil && filter.Author != "" {
-		query += `AND authors.name = $2 `
-		args = append(args, filter.Author)
-	}
-	query += `ORDER BY created_at DESC`
+	query += `WHERE a.id = ANY($1)`
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +363,7 @@ func (r *postgresRepository) GetArticlesByID(ctx context.Context, filter *Articl
 
 	for rows.Next() {
 		var article Article
-		if err := rows.Scan(&article.ID, &article.Title, &article.Body, &article.CreatedAt, &article.Author.ID, &article.Author.Name); err != nil {
+		if err := rows.Scan(articleFields(&article)...); err != nil {
 			return nil, err
 		}
 		articles = append(articles, &article)
@@ -120,3 +375,98 @@ func (r *postgresRepository) GetArticlesByID(ctx context.Context, filter *Articl
 
 	return articles, nil
 }
+
+// GetArticleByID retrieves a single article by its ID.
+// It returns sql.ErrNoRows when the article does not exist.
+func (r *postgresRepository) GetArticleByID(ctx context.Context, id string) (*Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles a `
+	query += `JOIN authors ON a.author_id = authors.id `
+	query += `WHERE a.id = $1`
+
+	var article Article
+	err := r.db.QueryRowContext(ctx, query, id).Scan(articleFields(&article)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &article, nil
+}
+
+// UpdateArticle overwrites the title, body and bylines of an existing article
+// together with its re-indexing outbox event. AuthorID must be the author of the first byline.
+// It returns sql.ErrNoRows when the article does not exist.
+func (r *postgresRepository) UpdateArticle(ctx context.Context, article *Article) (*Article, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE articles SET title = $1, body = $2, author_id = $3, updated_at = $4 WHERE id = $5 RETURNING created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query, article.Title, article.Body, article.AuthorID, article.UpdatedAt, article.ID).Scan(&article.CreatedAt, &article.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM article_authors WHERE article_id = $1`, article.ID); err != nil {
+		return nil, err
+	}
+	if err := insertBylines(ctx, tx, article); err != nil {
+		return nil, err
+	}
+
+	if err := outbox.Enqueue(ctx, tx, &outbox.Event{AggregateID: article.ID, EventType: EventArticleUpdated}); err != nil {
+		return nil, fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// DeleteArticle removes an article by its ID together with its de-indexing outbox event.
+// It returns sql.ErrNoRows when the article does not exist.
+func (r *postgresRepository) DeleteArticle(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM articles WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := outbox.Enqueue(ctx, tx, &outbox.Event{AggregateID: id, EventType: EventArticleDeleted}); err != nil {
+		return fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// insertBylines credits the authors of an article, positioned in the order of article.Authors.
+func insertBylines(ctx context.Context, tx *sql.Tx, article *Article) error {
+	authorIDs := make([]string, len(article.Authors))
+	roles := make([]string, len(article.Authors))
+	for i, byline := range article.Authors {
+		authorIDs[i] = byline.ID
+		roles[i] = byline.Role
+	}
+
+	query := `INSERT INTO article_authors (article_id, author_id, role, position)
+		SELECT $1, byline.author_id, byline.role, byline.position
+		FROM unnest($2::uuid[], $3::text[]) WITH ORDINALITY AS byline(author_id, role, position)`
+	_, err := tx.ExecContext(ctx, query, article.ID, pq.Array(authorIDs), pq.Array(roles))
+	return err
+}
@@ -2,17 +2,50 @@ package article_test
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"kumparan-test/internal/article"
+	"kumparan-test/internal/author"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+// selectArticles matches the columns the repository selects for every article, with its lead author's profile and its bylines.
+const selectArticles = `SELECT a\.id, a\.title, a\.body, a\.created_at, a\.updated_at, authors\.id, authors\.name, authors\.slug, ` +
+	`authors\.bio, authors\.avatar_url, authors\.social_handles, authors\.created_at, authors\.updated_at, ` +
+	`\(SELECT json_agg\(.+ ORDER BY aa\.position\) FROM article_authors aa JOIN authors au ON au\.id = aa\.author_id WHERE aa\.article_id = a\.id\)`
+
+// articleColumns name the result columns of selectArticles.
+var articleColumns = []string{
+	"id", "title", "body", "created_at", "updated_at", "author_id", "author_name", "author_slug", "author_bio",
+	"author_avatar_url", "author_social_handles", "author_created_at", "author_updated_at", "bylines",
+}
+
+// articleRow returns the values of an article row written by a single author with an empty profile,
+// followed by any extra values.
+func articleRow(id, title, body, authorID, authorName string, at time.Time, extra ...driver.Value) []driver.Value {
+	bylines := fmt.Sprintf(`[{"id": %q, "name": %q, "role": "writer"}]`, authorID, authorName)
+	row := []driver.Value{id, title, body, at, at, authorID, authorName, "", "", "", []byte("{}"), at, at, []byte(bylines)}
+	return append(row, extra...)
+}
+
+// creditsAuthorName and creditsAuthorID match the conditions of the author filters on argument $n.
+func creditsAuthorName(n int) string {
+	return fmt.Sprintf(`EXISTS \(SELECT 1 FROM article_authors aa JOIN authors au ON au\.id = aa\.author_id `+
+		`WHERE aa\.article_id = a\.id AND au\.name_key = ANY\(\$%d\)\)`, n)
+}
+
+func creditsAuthorID(n int) string {
+	return fmt.Sprintf(`EXISTS \(SELECT 1 FROM article_authors aa WHERE aa\.article_id = a\.id AND aa\.author_id = ANY\(\$%d\)\)`, n)
+}
+
 func setupRepoWithMock(t *testing.T) (article.Repository, sqlmock.Sqlmock, func()) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -27,16 +60,28 @@ func TestCreateArticle_Success(t *testing.T) {
 	defer cleanup()
 
 	art := &article.Article{
-		Title:     "Test Title",
-		Body:      "Test Body",
-		AuthorID:  "author-123",
+		Title:    "Test Title",
+		Body:     "Test Body",
+		AuthorID: "author-123",
+		Authors: article.Bylines{
+			{Author: author.Author{ID: "author-123"}, Role: article.RoleWriter},
+			{Author: author.Author{ID: "author-456"}, Role: article.RolePhotographer},
+		},
 		CreatedAt: time.Now(),
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO articles`).
 		WithArgs(art.Title, art.Body, art.AuthorID, art.CreatedAt).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).
-			AddRow("article-456", art.CreatedAt))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+			AddRow("article-456", art.CreatedAt, art.CreatedAt))
+	mock.ExpectExec(`INSERT INTO article_authors \(article_id, author_id, role, position\)`).
+		WithArgs("article-456", pq.Array([]string{"author-123", "author-456"}), pq.Array([]string{"writer", "photographer"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO outbox_events \(aggregate_id, event_type\)`).
+		WithArgs("article-456", article.EventArticleCreated).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	result, err := repo.CreateArticle(context.Background(), art)
 	assert.NoError(t, err)
@@ -57,33 +102,149 @@ func TestCreateArticle_Fails(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO articles`).
 		WithArgs(art.Title, art.Body, art.AuthorID, art.CreatedAt).
 		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
 
 	_, err := repo.CreateArticle(context.Background(), art)
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateArticle_OutboxFailsRollsBack(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	art := &article.Article{
+		Title:    "Test Title",
+		Body:     "Test Body",
+		AuthorID: "author-123",
+		Authors: article.Bylines{
+			{Author: author.Author{ID: "author-123"}, Role: article.RoleWriter},
+			{Author: author.Author{ID: "author-456"}, Role: article.RolePhotographer},
+		},
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO articles`).
+		WithArgs(art.Title, art.Body, art.AuthorID, art.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+			AddRow("article-456", art.CreatedAt, art.CreatedAt))
+	mock.ExpectExec(`INSERT INTO article_authors \(article_id, author_id, role, position\)`).
+		WithArgs("article-456", pq.Array([]string{"author-123", "author-456"}), pq.Array([]string{"writer", "photographer"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO outbox_events`).
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	_, err := repo.CreateArticle(context.Background(), art)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetArticles_Success(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	filter := &article.ArticleFilter{Page: 1, Limit: 2, Author: "Bara"}
+	filter := &article.ArticleFilter{Page: 1, Limit: 2, Authors: []string{"Bara"}}
 
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "body", "id", "name", "created_at",
-	}).AddRow("a1", "T1", "B1", "auth1", "Bara", time.Now()).
-		AddRow("a2", "T2", "B2", "auth2", "Bara", time.Now())
+	rows := sqlmock.NewRows(append(articleColumns, "count")).AddRow(articleRow("a1", "T1", "B1", "auth1", "Bara", time.Now(), 5)...).
+		AddRow(articleRow("a2", "T2", "B2", "auth2", "Bara", time.Now(), 5)...)
 
-	mock.ExpectQuery(`SELECT a\.id, a\.title, a\.body, authors\.id, authors\.name, a\.created_at`).
-		WithArgs("Bara", 2, 0).
+	mock.ExpectQuery(selectArticles+`, COUNT\(\*\) OVER\(\)`).
+		WithArgs(pq.Array([]string{"bara"}), 2, 0).
 		WillReturnRows(rows)
 
-	results, err := repo.GetArticles(context.Background(), filter)
+	results, total, err := repo.GetArticles(context.Background(), filter)
 	assert.NoError(t, err)
 	assert.Len(t, results, 2)
+	assert.Equal(t, int64(5), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticles_AuthorFilterIgnoresCaseAndSpacing(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	filter := &article.ArticleFilter{Page: 1, Limit: 10, Authors: []string{" budi  SANTOSO"}}
+
+	mock.ExpectQuery(selectArticles+`, COUNT\(\*\) OVER\(\) FROM articles a JOIN authors ON a\.author_id = authors\.id WHERE `+creditsAuthorName(1)).
+		WithArgs(pq.Array([]string{"budi santoso"}), 10, 0).
+		WillReturnRows(sqlmock.NewRows(append(articleColumns, "count")).
+			AddRow(articleRow("a1", "T1", "B1", "auth1", "Budi Santoso", time.Now(), 1)...))
+
+	results, total, err := repo.GetArticles(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(1), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticles_PastLastPageStillCounts(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	filter := &article.ArticleFilter{Page: 4, Limit: 2, Authors: []string{"Bara"}}
+
+	mock.ExpectQuery(selectArticles+`, COUNT\(\*\) OVER\(\)`).
+		WithArgs(pq.Array([]string{"bara"}), 2, 6).
+		WillReturnRows(sqlmock.NewRows(append(articleColumns, "count")))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM articles a JOIN authors ON a\.author_id = authors\.id WHERE ` + creditsAuthorName(1)).
+		WithArgs(pq.Array([]string{"bara"})).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	results, total, err := repo.GetArticles(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, results, 0)
+	assert.Equal(t, int64(5), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticles_WithAllFilters(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	filter := &article.ArticleFilter{
+		Page:      1,
+		Limit:     10,
+		Authors:   []string{"Bara", "Sari"},
+		AuthorIDs: []string{"auth-1"},
+		From:      from,
+		To:        to,
+	}
+
+	mock.ExpectQuery(`WHERE `+creditsAuthorName(1)+` AND `+creditsAuthorID(2)+` AND a\.created_at >= \$3 AND a\.created_at < \$4 ORDER BY`).
+		WithArgs(pq.Array([]string{"bara", "sari"}), pq.Array([]string{"auth-1"}), from, to, 10, 0).
+		WillReturnRows(sqlmock.NewRows(append(articleColumns, "count")).
+			AddRow(articleRow("a1", "T1", "B1", "auth-1", "Bara", from, 1)...))
+
+	results, total, err := repo.GetArticles(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(1), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticles_QueryAndAuthor(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	filter := &article.ArticleFilter{Query: "banjir", Authors: []string{"Bara"}, Page: 3, Limit: 2, Sort: article.SortNewest}
+
+	mock.ExpectQuery(`WHERE `+creditsAuthorName(1)+` AND a\.search_vector @@ websearch_to_tsquery\('simple', \$2\) ORDER BY a\.created_at DESC, a\.id DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs(pq.Array([]string{"bara"}), "banjir", 2, 4).
+		WillReturnRows(sqlmock.NewRows(append(articleColumns, "count")).
+			AddRow(articleRow("a5", "T5", "B5", "auth1", "Bara", time.Now(), 5)...))
+
+	results, total, err := repo.GetArticles(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(5), total)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -91,7 +252,7 @@ func TestGetArticles_ScanError(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	query := `SELECT a.id, a.title, a.body, authors.id, authors.name, a.created_at FROM articles a JOIN authors ON a.author_id = authors.id ORDER BY created_at DESC LIMIT \$1 OFFSET \$2`
+	query := selectArticles + `, COUNT\(\*\) OVER\(\) FROM articles a JOIN authors ON a.author_id = authors.id ORDER BY a.created_at DESC, a.id DESC LIMIT \$1 OFFSET \$2`
 
 	mock.ExpectQuery(query).
 		WithArgs(10, 0).
@@ -99,7 +260,7 @@ func TestGetArticles_ScanError(t *testing.T) {
 			AddRow("id-1", "Test Title"))
 
 	filter := &article.ArticleFilter{Page: 1, Limit: 10}
-	articles, err := repo.GetArticles(context.Background(), filter)
+	articles, _, err := repo.GetArticles(context.Background(), filter)
 
 	assert.Error(t, err)
 	assert.Nil(t, articles)
@@ -110,17 +271,17 @@ func TestGetArticles_RowsError(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	query := `SELECT a.id, a.title, a.body, authors.id, authors.name, a.created_at FROM articles a JOIN authors ON a.author_id = authors.id ORDER BY created_at DESC LIMIT \$1 OFFSET \$2`
+	query := selectArticles + `, COUNT\(\*\) OVER\(\) FROM articles a JOIN authors ON a.author_id = authors.id ORDER BY a.created_at DESC, a.id DESC LIMIT \$1 OFFSET \$2`
 
-	rows := sqlmock.NewRows([]string{"id", "title", "body", "author_id", "author_name", "created_at"}).
-		AddRow("id-1", "Title", "Body", "auth-1", "Bagunda", time.Now())
+	rows := sqlmock.NewRows(append(articleColumns, "count")).
+		AddRow(articleRow("id-1", "Title", "Body", "auth-1", "Bagunda", time.Now(), 1)...)
 
 	mock.ExpectQuery(query).
 		WithArgs(10, 0).
 		WillReturnRows(rows.RowError(0, nil).CloseError(errors.New("rows iteration error")))
 
 	filter := &article.ArticleFilter{Page: 1, Limit: 10}
-	articles, err := repo.GetArticles(context.Background(), filter)
+	articles, _, err := repo.GetArticles(context.Background(), filter)
 
 	assert.ErrorContains(t, err, "rows iteration error")
 	assert.Nil(t, articles)
@@ -131,13 +292,13 @@ func TestGetArticles_DBError(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	filter := &article.ArticleFilter{Page: 1, Limit: 10, Author: "Biri"}
+	filter := &article.ArticleFilter{Page: 1, Limit: 10, Authors: []string{"Biri"}}
 
-	mock.ExpectQuery(`SELECT a\.id, a\.title, a\.body, authors\.id, authors\.name, a\.created_at`).
-		WithArgs("Biri", 10, 0).
+	mock.ExpectQuery(selectArticles).
+		WithArgs(pq.Array([]string{"biri"}), 10, 0).
 		WillReturnError(assert.AnError)
 
-	_, err := repo.GetArticles(context.Background(), filter)
+	_, _, err := repo.GetArticles(context.Background(), filter)
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -146,19 +307,16 @@ func TestGetArticlesByID_Success(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	filter := &article.ArticleFilter{Author: "Bara"}
 	ids := []string{"id-1", "id-2"}
 
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "body", "created_at", "id", "name",
-	}).AddRow("id-1", "T1", "B1", time.Now(), "auth1", "Bara").
-		AddRow("id-2", "T2", "B2", time.Now(), "auth2", "Bara")
+	rows := sqlmock.NewRows(articleColumns).AddRow(articleRow("id-1", "T1", "B1", "auth1", "Bara", time.Now())...).
+		AddRow(articleRow("id-2", "T2", "B2", "auth2", "Bara", time.Now())...)
 
-	mock.ExpectQuery(`SELECT a\.id, a\.title, a\.body, a\.created_at, authors\.id, authors\.name`).
-		WithArgs(sqlmock.AnyArg(), "Bara").
+	mock.ExpectQuery(selectArticles).
+		WithArgs(pq.Array(ids)).
 		WillReturnRows(rows)
 
-	result, err := repo.GetArticlesByID(context.Background(), filter, ids)
+	result, err := repo.GetArticlesByID(context.Background(), ids)
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -168,10 +326,9 @@ func TestGetArticlesByID_EmptyInput(t *testing.T) {
 	repo, _, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	filter := &article.ArticleFilter{}
 	ids := []string{}
 
-	result, err := repo.GetArticlesByID(context.Background(), filter, ids)
+	result, err := repo.GetArticlesByID(context.Background(), ids)
 	assert.NoError(t, err)
 	assert.Len(t, result, 0)
 }
@@ -181,14 +338,13 @@ func TestGetArticlesByID_ScanError(t *testing.T) {
 	defer cleanup()
 
 	ids := []string{"id1", "id2"}
-	filter := &article.ArticleFilter{Page: 1, Limit: 10}
 
-	mock.ExpectQuery(`SELECT a.id, a.title, a.body, a.created_at, authors.id, authors.name FROM articles a .*WHERE a.id = ANY\(\$1\).*`).
+	mock.ExpectQuery(selectArticles + ` FROM articles a .*WHERE a.id = ANY\(\$1\).*`).
 		WithArgs(pq.Array(ids)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).
 			AddRow("id1", "Some Title"))
 
-	articles, err := repo.GetArticlesByID(context.Background(), filter, ids)
+	articles, err := repo.GetArticlesByID(context.Background(), ids)
 
 	assert.Error(t, err)
 	assert.Nil(t, articles)
@@ -201,18 +357,17 @@ func TestGetArticlesByID_RowsError(t *testing.T) {
 
 	now := time.Now()
 	ids := []string{"id1"}
-	filter := &article.ArticleFilter{}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "body", "created_at", "author_id", "author_name"}).
-		AddRow("id1", "Title", "Body", now, "auth-1", "Author").
+	rows := sqlmock.NewRows(articleColumns).
+		AddRow(articleRow("id1", "Title", "Body", "auth-1", "Author", now)...).
 		RowError(0, nil)
 	rows.CloseError(errors.New("rows iteration error"))
 
-	mock.ExpectQuery(`SELECT a.id, a.title, a.body, a.created_at, authors.id, authors.name FROM articles a .*WHERE a.id = ANY\(\$1\).*`).
+	mock.ExpectQuery(selectArticles + ` FROM articles a .*WHERE a.id = ANY\(\$1\).*`).
 		WithArgs(pq.Array(ids)).
 		WillReturnRows(rows)
 
-	articles, err := repo.GetArticlesByID(context.Background(), filter, ids)
+	articles, err := repo.GetArticlesByID(context.Background(), ids)
 
 	assert.ErrorContains(t, err, "rows iteration error")
 	assert.Nil(t, articles)
@@ -223,14 +378,370 @@ func TestGetArticlesByID_DBError(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	filter := &article.ArticleFilter{}
 	ids := []string{"id-1"}
 
-	mock.ExpectQuery(`SELECT a\.id, a\.title, a\.body, a\.created_at, authors\.id, authors\.name`).
+	mock.ExpectQuery(selectArticles).
 		WithArgs(sqlmock.AnyArg()).
 		WillReturnError(assert.AnError)
 
-	_, err := repo.GetArticlesByID(context.Background(), filter, ids)
+	_, err := repo.GetArticlesByID(context.Background(), ids)
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetArticleByID_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows(articleColumns).AddRow(articleRow("id-1", "T1", "B1", "auth1", "Bara", time.Now())...)
+
+	mock.ExpectQuery(selectArticles + ` FROM articles a .*WHERE a\.id = \$1`).
+		WithArgs("id-1").
+		WillReturnRows(rows)
+
+	result, err := repo.GetArticleByID(context.Background(), "id-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "id-1", result.ID)
+	assert.Equal(t, "Bara", result.Author.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticleByID_ReadsBylines(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	row := articleRow("id-1", "T1", "B1", "auth1", "Bara", time.Now())
+	row[len(row)-1] = []byte(`[
+		{"id": "auth1", "name": "Bara", "slug": "bara", "social_handles": {"twitter": "bara"}, "created_at": "2025-01-06T08:00:00+00:00", "role": "writer"},
+		{"id": "auth2", "name": "Sari", "slug": "sari", "social_handles": {}, "created_at": "2025-01-06T15:00:00+07:00", "role": "photographer"}
+	]`)
+	mock.ExpectQuery(selectArticles + ` FROM articles a .*WHERE a\.id = \$1`).
+		WithArgs("id-1").
+		WillReturnRows(sqlmock.NewRows(articleColumns).AddRow(row...))
+
+	result, err := repo.GetArticleByID(context.Background(), "id-1")
+	assert.NoError(t, err)
+	assert.Len(t, result.Authors, 2)
+	assert.Equal(t, "bara", result.Authors[0].SocialHandles.Twitter)
+	assert.Equal(t, "Sari", result.Authors[1].Name)
+	assert.Equal(t, article.RolePhotographer, result.Authors[1].Role)
+	assert.True(t, result.Authors[0].CreatedAt.Equal(result.Authors[1].CreatedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticleByID_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(selectArticles).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	result, err := repo.GetArticleByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateArticle_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	createdAt := time.Now().Add(-time.Hour)
+	art := &article.Article{
+		ID:        "id-1",
+		Title:     "New Title",
+		Body:      "New Body",
+		AuthorID:  "auth-1",
+		Authors:   article.Bylines{{Author: author.Author{ID: "auth-1"}, Role: article.RoleWriter}},
+		UpdatedAt: time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE articles SET title = \$1, body = \$2, author_id = \$3, updated_at = \$4 WHERE id = \$5`).
+		WithArgs(art.Title, art.Body, art.AuthorID, art.UpdatedAt, art.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).
+			AddRow(createdAt, art.UpdatedAt))
+	// The bylines are replaced as a whole
+	mock.ExpectExec(`DELETE FROM article_authors WHERE article_id = \$1`).
+		WithArgs("id-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO article_authors`).
+		WithArgs("id-1", pq.Array([]string{"auth-1"}), pq.Array([]string{"writer"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO outbox_events`).
+		WithArgs("id-1", article.EventArticleUpdated).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.UpdateArticle(context.Background(), art)
+	assert.NoError(t, err)
+	assert.Equal(t, createdAt, result.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateArticle_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	art := &article.Article{ID: "missing", Title: "T", Body: "B", AuthorID: "auth-1", UpdatedAt: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE articles`).
+		WithArgs(art.Title, art.Body, art.AuthorID, art.UpdatedAt, art.ID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	result, err := repo.UpdateArticle(context.Background(), art)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteArticle_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM articles WHERE id = \$1`).
+		WithArgs("id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO outbox_events`).
+		WithArgs("id-1", article.EventArticleDeleted).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.DeleteArticle(context.Background(), "id-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteArticle_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM articles WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.DeleteArticle(context.Background(), "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteArticle_DBError(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM articles`).
+		WithArgs("id-1").
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	err := repo.DeleteArticle(context.Background(), "id-1")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticlesAfter_FirstPageHasNext(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	filter := &article.ArticleFilter{Limit: 2}
+
+	rows := sqlmock.NewRows(articleColumns).
+		AddRow(articleRow("a3", "T3", "B3", "auth1", "Bara", time.Now())...).
+		AddRow(articleRow("a2", "T2", "B2", "auth1", "Bara", time.Now())...).
+		AddRow(articleRow("a1", "T1", "B1", "auth1", "Bara", time.Now())...)
+
+	mock.ExpectQuery(selectArticles + ` FROM articles a JOIN authors ON a\.author_id = authors\.id ORDER BY a\.created_at DESC, a\.id DESC LIMIT \$1`).
+		WithArgs(3).
+		WillReturnRows(rows)
+
+	results, hasNext, err := repo.GetArticlesAfter(context.Background(), filter, nil)
+	assert.NoError(t, err)
+	assert.True(t, hasNext)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "a2", results[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticlesAfter_WithCursorAndAuthor(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	filter := &article.ArticleFilter{Limit: 2, Authors: []string{"Bara"}}
+	cursor := &article.Cursor{CreatedAt: time.Now(), ID: "a3"}
+
+	rows := sqlmock.NewRows(articleColumns).
+		AddRow(articleRow("a2", "T2", "B2", "auth1", "Bara", time.Now())...)
+
+	mock.ExpectQuery(`WHERE `+creditsAuthorName(1)+` AND \(a\.created_at, a\.id\) < \(\$2, \$3\) ORDER BY a\.created_at DESC, a\.id DESC LIMIT \$4`).
+		WithArgs(pq.Array([]string{"bara"}), cursor.CreatedAt, "a3", 3).
+		WillReturnRows(rows)
+
+	results, hasNext, err := repo.GetArticlesAfter(context.Background(), filter, cursor)
+	assert.NoError(t, err)
+	assert.False(t, hasNext)
+	assert.Len(t, results, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticlesAfter_DBError(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT a\.id`).
+		WillReturnError(assert.AnError)
+
+	_, _, err := repo.GetArticlesAfter(context.Background(), &article.ArticleFilter{Limit: 2}, nil)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountArticles_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM articles a JOIN authors ON a\.author_id = authors\.id WHERE ` + creditsAuthorName(1)).
+		WithArgs(pq.Array([]string{"bara"})).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	total, err := repo.CountArticles(context.Background(), &article.ArticleFilter{Authors: []string{"Bara"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticlesAfter_OldestFirst(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	filter := &article.ArticleFilter{Limit: 2, Sort: article.SortOldest}
+	cursor := &article.Cursor{Sort: article.SortOldest, CreatedAt: time.Now(), ID: "a1"}
+
+	rows := sqlmock.NewRows(articleColumns).
+		AddRow(articleRow("a2", "T2", "B2", "auth1", "Bara", time.Now())...)
+
+	mock.ExpectQuery(`WHERE \(a\.created_at, a\.id\) > \(\$1, \$2\) ORDER BY a\.created_at ASC, a\.id ASC LIMIT \$3`).
+		WithArgs(cursor.CreatedAt, "a1", 3).
+		WillReturnRows(rows)
+
+	results, hasNext, err := repo.GetArticlesAfter(context.Background(), filter, cursor)
+	assert.NoError(t, err)
+	assert.False(t, hasNext)
+	assert.Len(t, results, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticles_OldestFirst(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	filter := &article.ArticleFilter{Page: 1, Limit: 10, Sort: article.SortOldest}
+
+	rows := sqlmock.NewRows(append(articleColumns, "count")).
+		AddRow(articleRow("a1", "T1", "B1", "auth1", "Bara", time.Now(), 1)...)
+
+	mock.ExpectQuery(`ORDER BY a\.created_at ASC, a\.id ASC LIMIT \$1 OFFSET \$2`).
+		WithArgs(10, 0).
+		WillReturnRows(rows)
+
+	results, total, err := repo.GetArticles(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(1), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticlesInIDOrder_AfterID(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows(articleColumns).
+		AddRow(articleRow("a2", "T2", "B2", "auth1", "Bara", time.Now())...).
+		AddRow(articleRow("a3", "T3", "B3", "auth1", "Bara", time.Now())...)
+
+	mock.ExpectQuery(selectArticles+` FROM articles a JOIN authors ON a\.author_id = authors\.id WHERE a\.id > \$1 ORDER BY a\.id LIMIT \$2`).
+		WithArgs("a1", 2).
+		WillReturnRows(rows)
+
+	articles, err := repo.GetArticlesInIDOrder(context.Background(), "a1", 2)
+	assert.NoError(t, err)
+	assert.Len(t, articles, 2)
+	assert.Equal(t, "a3", articles[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticlesInIDOrder_FromStart(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(selectArticles + ` FROM articles a JOIN authors ON a\.author_id = authors\.id ORDER BY a\.id LIMIT \$1`).
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows(articleColumns))
+
+	articles, err := repo.GetArticlesInIDOrder(context.Background(), "", 100)
+	assert.NoError(t, err)
+	assert.Empty(t, articles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticlesChangedSince(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	since := time.Now().Add(-time.Hour)
+	rows := sqlmock.NewRows(articleColumns).
+		AddRow(articleRow("a2", "T2", "B2", "auth1", "Bara", time.Now())...)
+
+	mock.ExpectQuery(selectArticles+` FROM articles a JOIN authors ON a\.author_id = authors\.id `+
+		`WHERE \(a\.updated_at >= \$1 OR EXISTS \(SELECT 1 FROM article_authors aa JOIN authors au ON au\.id = aa\.author_id `+
+		`WHERE aa\.article_id = a\.id AND au\.updated_at >= \$1\)\) AND a\.id > \$2 ORDER BY a\.id LIMIT \$3`).
+		WithArgs(since, "a1", 100).
+		WillReturnRows(rows)
+
+	articles, err := repo.GetArticlesChangedSince(context.Background(), since, "a1", 100)
+	assert.NoError(t, err)
+	assert.Len(t, articles, 1)
+	assert.Equal(t, "Bara", articles[0].Authors[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeletedArticleIDs(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	since := time.Now().Add(-time.Hour)
+	mock.ExpectQuery(`SELECT DISTINCT aggregate_id FROM outbox_events WHERE event_type = \$1 AND created_at >= \$2`).
+		WithArgs(article.EventArticleDeleted, since).
+		WillReturnRows(sqlmock.NewRows([]string{"aggregate_id"}).AddRow("a1").AddRow("a4"))
+
+	ids, err := repo.GetDeletedArticleIDs(context.Background(), since)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a1", "a4"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticles_FullTextFallbackByRelevance(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	filter := &article.ArticleFilter{Query: "banjir jakarta", Page: 1, Limit: 10, Sort: article.SortRelevance}
+
+	rows := sqlmock.NewRows(append(articleColumns, "count")).
+		AddRow(articleRow("a1", "Banjir", "Jakarta", "auth1", "Bara", time.Now(), 1)...)
+
+	mock.ExpectQuery(`WHERE a\.search_vector @@ websearch_to_tsquery\('simple', \$1\) ORDER BY ts_rank\(a\.search_vector, websearch_to_tsquery\('simple', \$2\)\) DESC, a\.created_at DESC, a\.id DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs("banjir jakarta", "banjir jakarta", 10, 0).
+		WillReturnRows(rows)
+
+	results, total, err := repo.GetArticles(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(1), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,132 @@
+package article
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"kumparan-test/internal/outbox"
+
+	"github.com/lib/pq"
+)
+
+type SavedSearchRepository interface {
+	CreateSavedSearch(ctx context.Context, saved *SavedSearch) (*SavedSearch, error)
+	GetSavedSearches(ctx context.Context) ([]*SavedSearch, error)
+	GetSavedSearchesByID(ctx context.Context, ids []string) ([]*SavedSearch, error) // For fetching saved searches from percolator matches
+	GetSavedSearchByID(ctx context.Context, id string) (*SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id string) error
+}
+
+type postgresSavedSearchRepository struct {
+	db *sql.DB
+}
+
+// NewSavedSearchRepository creates a new PostgreSQL repository of saved searches.
+func NewSavedSearchRepository(db *sql.DB) SavedSearchRepository {
+	return &postgresSavedSearchRepository{db: db}
+}
+
+// CreateSavedSearch inserts a new saved search together with the outbox event that registers it for alerts.
+func (r *postgresSavedSearchRepository) CreateSavedSearch(ctx context.Context, saved *SavedSearch) (*SavedSearch, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO saved_searches (name, query) VALUES ($1, $2) RETURNING id, created_at`
+	if err := tx.QueryRowContext(ctx, query, saved.Name, saved.Query).Scan(&saved.ID, &saved.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := outbox.Enqueue(ctx, tx, &outbox.Event{AggregateID: saved.ID, EventType: EventSavedSearchCreated}); err != nil {
+		return nil, fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// GetSavedSearches retrieves every saved search, newest first.
+func (r *postgresSavedSearchRepository) GetSavedSearches(ctx context.Context) ([]*SavedSearch, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, query, created_at FROM saved_searches ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	return scanSavedSearches(rows)
+}
+
+// GetSavedSearchesByID retrieves the saved searches with the given IDs, skipping IDs that do not exist.
+func (r *postgresSavedSearchRepository) GetSavedSearchesByID(ctx context.Context, ids []string) ([]*SavedSearch, error) {
+	if len(ids) == 0 {
+		return []*SavedSearch{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, query, created_at FROM saved_searches WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	return scanSavedSearches(rows)
+}
+
+// GetSavedSearchByID retrieves a single saved search, returning sql.ErrNoRows if it does not exist.
+func (r *postgresSavedSearchRepository) GetSavedSearchByID(ctx context.Context, id string) (*SavedSearch, error) {
+	var saved SavedSearch
+	err := r.db.QueryRowContext(ctx, `SELECT id, name, query, created_at FROM saved_searches WHERE id = $1`, id).
+		Scan(&saved.ID, &saved.Name, &saved.Query, &saved.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// DeleteSavedSearch removes a saved search together with the outbox event that unregisters it,
+// returning sql.ErrNoRows if it does not exist.
+func (r *postgresSavedSearchRepository) DeleteSavedSearch(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := outbox.Enqueue(ctx, tx, &outbox.Event{AggregateID: id, EventType: EventSavedSearchDeleted}); err != nil {
+		return fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// scanSavedSearches reads every saved search from rows and closes them.
+func scanSavedSearches(rows *sql.Rows) ([]*SavedSearch, error) {
+	defer rows.Close()
+
+	searches := []*SavedSearch{}
+	for rows.Next() {
+		var saved SavedSearch
+		if err := rows.Scan(&saved.ID, &saved.Name, &saved.Query, &saved.CreatedAt); err != nil {
+			return nil, err
+		}
+		searches = append(searches, &saved)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
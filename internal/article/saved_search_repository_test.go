@@ -0,0 +1,107 @@
+package article_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"kumparan-test/internal/article"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSavedSearchRepoWithMock(t *testing.T) (article.SavedSearchRepository, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	return article.NewSavedSearchRepository(db), mock, func() { db.Close() }
+}
+
+func TestCreateSavedSearch_EnqueuesOutboxEvent(t *testing.T) {
+	repo, mock, cleanup := setupSavedSearchRepoWithMock(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO saved_searches \(name, query\)`).
+		WithArgs("Banjir", "banjir jakarta").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("search-1", now))
+	mock.ExpectExec(`INSERT INTO outbox_events`).
+		WithArgs("search-1", article.EventSavedSearchCreated).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	saved, err := repo.CreateSavedSearch(context.Background(), &article.SavedSearch{Name: "Banjir", Query: "banjir jakarta"})
+	assert.NoError(t, err)
+	assert.Equal(t, "search-1", saved.ID)
+	assert.Equal(t, now, saved.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSavedSearchesByID(t *testing.T) {
+	repo, mock, cleanup := setupSavedSearchRepoWithMock(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, name, query, created_at FROM saved_searches WHERE id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]string{"search-1", "search-2"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "query", "created_at"}).
+			AddRow("search-1", "Banjir", "banjir jakarta", now))
+
+	searches, err := repo.GetSavedSearchesByID(context.Background(), []string{"search-1", "search-2"})
+	assert.NoError(t, err)
+	assert.Len(t, searches, 1)
+	assert.Equal(t, "banjir jakarta", searches[0].Query)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSavedSearchByID_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupSavedSearchRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT id, name, query, created_at FROM saved_searches WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.GetSavedSearchByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSavedSearch_Success(t *testing.T) {
+	repo, mock, cleanup := setupSavedSearchRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM saved_searches WHERE id = \$1`).
+		WithArgs("search-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO outbox_events`).
+		WithArgs("search-1", article.EventSavedSearchDeleted).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.DeleteSavedSearch(context.Background(), "search-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSavedSearch_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupSavedSearchRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM saved_searches WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.DeleteSavedSearch(context.Background(), "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,138 @@
+package article
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"kumparan-test/pkg/search"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+// Notifier delivers the alerts of saved searches, e.g. to a webhook or the log.
+type Notifier interface {
+	Notify(ctx context.Context, alert *Alert) error
+}
+
+type SavedSearchService interface {
+	CreateSavedSearch(ctx context.Context, req *CreateSavedSearchRequest) (*SavedSearch, error)
+	GetSavedSearches(ctx context.Context) (*SavedSearchList, error)
+	GetSavedSearchByID(ctx context.Context, id string) (*SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id string) error
+}
+
+type savedSearchService struct {
+	repo SavedSearchRepository
+}
+
+func NewSavedSearchService(repo SavedSearchRepository) SavedSearchService {
+	return &savedSearchService{repo: repo}
+}
+
+// CreateSavedSearch saves a search query. It is registered for alerts in the background, through the outbox.
+// It returns an error wrapping ErrInvalidQuery when the query is malformed.
+func (s *savedSearchService) CreateSavedSearch(ctx context.Context, req *CreateSavedSearchRequest) (*SavedSearch, error) {
+	if _, err := savedSearchQuery(req.Query); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.CreateSavedSearch(ctx, &SavedSearch{Name: req.Name, Query: req.Query})
+	if err != nil {
+		logrus.WithError(err).Error("Service failed to create saved search in DB")
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+
+	return saved, nil
+}
+
+// GetSavedSearches returns every saved search, newest first.
+func (s *savedSearchService) GetSavedSearches(ctx context.Context) (*SavedSearchList, error) {
+	searches, err := s.repo.GetSavedSearches(ctx)
+	if err != nil {
+		logrus.WithError(err).Error("Service failed to get saved searches from DB")
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+
+	return &SavedSearchList{Data: searches}, nil
+}
+
+// GetSavedSearchByID retrieves a single saved search, returning ErrSavedSearchNotFound if it does not exist.
+func (s *savedSearchService) GetSavedSearchByID(ctx context.Context, id string) (*SavedSearch, error) {
+	saved, err := s.repo.GetSavedSearchByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSavedSearchNotFound
+		}
+		logrus.WithError(err).WithField("saved_search_id", id).Error("Service failed to get saved search from DB")
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	return saved, nil
+}
+
+// DeleteSavedSearch removes a saved search, returning ErrSavedSearchNotFound if it does not exist.
+// Alerts stop once the outbox has unregistered it.
+func (s *savedSearchService) DeleteSavedSearch(ctx context.Context, id string) error {
+	err := s.repo.DeleteSavedSearch(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSavedSearchNotFound
+		}
+		logrus.WithError(err).WithField("saved_search_id", id).Error("Service failed to delete saved search in DB")
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+
+	return nil
+}
+
+// RegisterSavedSearches stores every saved search in the given percolator index, replacing
+// what it held for them. It fills a new index before it starts serving alerts.
+func RegisterSavedSearches(ctx context.Context, repo SavedSearchRepository, esClient search.SearchService, index string) error {
+	searches, err := repo.GetSavedSearches(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load saved searches: %w", err)
+	}
+
+	docs := make(map[string]interface{}, len(searches))
+	for _, saved := range searches {
+		doc, err := newSavedSearchDocument(saved)
+		if err != nil {
+			// Saved before the query syntax changed; it cannot alert until it is saved again
+			logrus.WithError(err).WithField("saved_search_id", saved.ID).Warn("Skipping saved search with an invalid query")
+			continue
+		}
+		docs[saved.ID] = doc
+	}
+
+	if err := esClient.BulkIndexDocuments(ctx, index, docs); err != nil {
+		return err
+	}
+	logrus.WithField("index", index).Infof("Registered %d saved searches", len(docs))
+	return nil
+}
+
+// savedSearchQuery builds the search query of a saved search, the same one a search with
+// that query and no other filter would run. Operators become filters, like they narrow searches.
+func savedSearchQuery(q string) (search.Query, error) {
+	parsed, err := parseQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &ArticleFilter{}
+	parsed.narrow(filter)
+	return searchQuery(filter, parsed), nil
+}
+
+// newSavedSearchDocument builds the percolator document of a saved search.
+func newSavedSearchDocument(saved *SavedSearch) (*search.SavedSearchDocument, error) {
+	query, err := savedSearchQuery(saved.Query)
+	if err != nil {
+		return nil, err
+	}
+	return &search.SavedSearchDocument{Query: query}, nil
+}
@@ -0,0 +1,88 @@
+package article_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"kumparan-test/internal/article"
+	"kumparan-test/internal/article/mocks"
+	"kumparan-test/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSavedSearch_Success(t *testing.T) {
+	mockRepo := new(mocks.MockSavedSearchRepo)
+	service := article.NewSavedSearchService(mockRepo)
+
+	mockRepo.On("CreateSavedSearch", mock.Anything, &article.SavedSearch{Name: "Banjir", Query: `banjir "jakarta utara"`}).
+		Return(&article.SavedSearch{ID: "search-1", Name: "Banjir", Query: `banjir "jakarta utara"`}, nil)
+
+	saved, err := service.CreateSavedSearch(context.Background(), &article.CreateSavedSearchRequest{Name: "Banjir", Query: `banjir "jakarta utara"`})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "search-1", saved.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateSavedSearch_InvalidQuery(t *testing.T) {
+	mockRepo := new(mocks.MockSavedSearchRepo)
+	service := article.NewSavedSearchService(mockRepo)
+
+	_, err := service.CreateSavedSearch(context.Background(), &article.CreateSavedSearchRequest{Name: "Banjir", Query: `banjir after:kemarin`})
+
+	assert.ErrorIs(t, err, article.ErrInvalidQuery)
+	mockRepo.AssertNotCalled(t, "CreateSavedSearch", mock.Anything, mock.Anything)
+}
+
+func TestGetSavedSearchByID_MapsNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockSavedSearchRepo)
+	service := article.NewSavedSearchService(mockRepo)
+
+	mockRepo.On("GetSavedSearchByID", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+	_, err := service.GetSavedSearchByID(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, article.ErrSavedSearchNotFound)
+}
+
+func TestDeleteSavedSearch_MapsNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockSavedSearchRepo)
+	service := article.NewSavedSearchService(mockRepo)
+
+	mockRepo.On("DeleteSavedSearch", mock.Anything, "missing").Return(sql.ErrNoRows)
+
+	err := service.DeleteSavedSearch(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, article.ErrSavedSearchNotFound)
+}
+
+func TestRegisterSavedSearches_StoresSearchQueries(t *testing.T) {
+	mockRepo := new(mocks.MockSavedSearchRepo)
+	mockSearch := new(mocks.MockSearchService)
+
+	mockRepo.On("GetSavedSearches", mock.Anything).Return([]*article.SavedSearch{
+		{ID: "search-1", Name: "Banjir", Query: "banjir author:Bara"},
+	}, nil)
+
+	var registered map[string]interface{}
+	mockSearch.On("BulkIndexDocuments", mock.Anything, "saved_searches_v4", mock.Anything).
+		Run(func(args mock.Arguments) { registered = args.Get(2).(map[string]interface{}) }).
+		Return(nil)
+
+	err := article.RegisterSavedSearches(context.Background(), mockRepo, mockSearch, "saved_searches_v4")
+
+	require.NoError(t, err)
+	require.Contains(t, registered, "search-1")
+	doc, ok := registered["search-1"].(*search.SavedSearchDocument)
+	require.True(t, ok)
+
+	// The stored query is the one a search for the same text runs, operators included
+	assert.Equal(t, &search.BoolQuery{
+		Must:   []search.Query{&search.MatchQuery{Text: "banjir", Fields: []string{"title^3", "body"}, Fuzzy: true}},
+		Filter: []search.Query{&search.TermsQuery{Field: search.ArticleFieldAuthorKey, Values: []string{"bara"}}},
+	}, doc.Query)
+}
@@ -2,53 +2,95 @@ package article
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"kumparan-test/internal/author"
 	"kumparan-test/pkg/search"
 
-	"github.com/olivere/elastic/v7"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	ErrArticleNotFound = errors.New("article not found")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+	ErrInvalidSort     = errors.New("invalid sort")
+	ErrInvalidFacet    = errors.New("invalid facet")
+	ErrInvalidQuery    = errors.New("invalid query")
+	ErrInvalidAuthors  = errors.New("invalid authors")
+	// ErrSearchUnavailable is returned when a search can only be served by Elasticsearch and it is down.
+	ErrSearchUnavailable = errors.New("search temporarily unavailable")
+)
+
 type Service interface {
 	PostArticle(ctx context.Context, req *CreateArticleRequest) (*Article, error)
-	GetArticles(ctx context.Context, filter *ArticleFilter) ([]*Article, error)
+	GetArticles(ctx context.Context, filter *ArticleFilter) (*ArticleList, error)
+	SuggestArticles(ctx context.Context, prefix string, limit int) (*Suggestions, error)
+	GetRelatedArticles(ctx context.Context, id string, limit int, preferSameAuthor bool) (*RelatedArticles, error)
+	GetArticleByID(ctx context.Context, id string) (*Article, error)
+	UpdateArticle(ctx context.Context, id string, req *UpdateArticleRequest) (*Article, error)
+	PatchArticle(ctx context.Context, id string, req *PatchArticleRequest) (*Article, error)
+	DeleteArticle(ctx context.Context, id string) error
+}
+
+// SearchConfig tunes full-text search results. Zero values fall back to sensible defaults.
+type SearchConfig struct {
+	HighlightFragmentSize int // Characters per highlighted fragment
+	HighlightFragments    int // Maximum highlighted fragments per field
+	HighlightPreTag       string
+	HighlightPostTag      string
+	SuggestTimeout        time.Duration // Time budget of an autocomplete search
+	Backend               string        // Search backend reported on listings it answers, BackendElasticsearch by default
 }
 
 type articleService struct {
 	repo          Repository
 	authorService author.Service
 	esClient      search.SearchService
+	searchCfg     SearchConfig
 }
 
-func NewArticleService(repo Repository, authorSvc author.Service, esClient search.SearchService) Service {
+func NewArticleService(repo Repository, authorSvc author.Service, esClient search.SearchService, searchCfg SearchConfig) Service {
+	if searchCfg.HighlightFragmentSize <= 0 {
+		searchCfg.HighlightFragmentSize = 150
+	}
+	if searchCfg.HighlightFragments <= 0 {
+		searchCfg.HighlightFragments = 3
+	}
+	if searchCfg.HighlightPreTag == "" || searchCfg.HighlightPostTag == "" {
+		searchCfg.HighlightPreTag, searchCfg.HighlightPostTag = "<em>", "</em>"
+	}
+	if searchCfg.SuggestTimeout <= 0 {
+		searchCfg.SuggestTimeout = 200 * time.Millisecond
+	}
+	if searchCfg.Backend == "" {
+		searchCfg.Backend = BackendElasticsearch
+	}
+
 	return &articleService{
 		repo:          repo,
 		authorService: authorSvc,
 		esClient:      esClient,
+		searchCfg:     searchCfg,
 	}
 }
 
 func (s *articleService) PostArticle(ctx context.Context, req *CreateArticleRequest) (*Article, error) {
-
-	authorObj, err := s.authorService.GetOrCreateAuthor(ctx, req.Author)
+	bylines, err := s.resolveBylines(ctx, req.Author, req.Authors)
 	if err != nil {
-		logrus.WithError(err).Error("Failed to get or create author for article")
-		return nil, fmt.Errorf("%w: failed to resolve author", err) // Wrap and return original error
+		return nil, err
 	}
 
 	article := &Article{
-		Title: req.Title,
-		Body:  req.Body,
-		Author: author.Author{
-			ID:   authorObj.ID,
-			Name: req.Author,
-		},
-		AuthorID:  authorObj.ID,
+		Title:     req.Title,
+		Body:      req.Body,
 		CreatedAt: time.Now(),
 	}
+	article.setBylines(bylines)
 
 	createdArticle, err := s.repo.CreateArticle(ctx, article)
 	if err != nil {
@@ -56,28 +98,69 @@ func (s *articleService) PostArticle(ctx context.Context, req *CreateArticleRequ
 		return nil, fmt.Errorf("failed to post article: %w", err)
 	}
 
-	// Index in Elasticsearch (synchronously for simplicity)
-	// In a high-throughput system, this would be asynchronous via a message queue
-	// to avoid blocking the API response and ensure reliability.
-	esDoc := map[string]interface{}{
-		"id":         createdArticle.ID,
-		"title":      createdArticle.Title,
-		"body":       createdArticle.Body,
-		"author":     createdArticle.Author.Name,
-		"created_at": createdArticle.CreatedAt,
+	// Indexing in Elasticsearch happens asynchronously: the repository writes an outbox
+	// event in the same transaction, which the outbox worker delivers with retries.
+	logrus.WithField("article_id", createdArticle.ID).Info("Article created, queued for indexing")
+
+	return createdArticle, nil
+}
+
+// maxBylines bounds how many authors can be credited on an article.
+const maxBylines = 10
+
+// validRoles are the roles an author can be credited with.
+var validRoles = map[string]bool{RoleWriter: true, RoleEditor: true, RolePhotographer: true}
+
+// resolveBylines gets or creates the authors credited by an article request, in credit order. A single author
+// name is a shorthand for a lone writer. The whole list is validated before any author is created, returning
+// ErrInvalidAuthors when it is empty or too long, has a nameless entry or an unknown role, or credits an
+// author twice.
+func (s *articleService) resolveBylines(ctx context.Context, name string, reqs []BylineRequest) (Bylines, error) {
+	if name != "" {
+		if len(reqs) > 0 {
+			return nil, fmt.Errorf("%w: give either author or authors, not both", ErrInvalidAuthors)
+		}
+		reqs = []BylineRequest{{Name: name}}
 	}
-	err = s.esClient.IndexDocument(ctx, search.ArticleIndexName, createdArticle.ID, esDoc)
-	if err != nil {
-		logrus.WithError(err).WithField("article_id", createdArticle.ID).
-			Error("Failed to index article in Elasticsearch")
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("%w: at least one author is required", ErrInvalidAuthors)
+	}
+	if len(reqs) > maxBylines {
+		return nil, fmt.Errorf("%w: at most %d authors can be credited", ErrInvalidAuthors, maxBylines)
 	}
 
-	logrus.WithField("article_id", createdArticle.ID).Info("Article indexed in Elasticsearch")
+	credited := map[string]bool{}
+	for _, req := range reqs {
+		if author.NormalizeName(req.Name) == "" {
+			return nil, fmt.Errorf("%w: every author needs a name", ErrInvalidAuthors)
+		}
+		if req.Role != "" && !validRoles[req.Role] {
+			return nil, fmt.Errorf("%w: unknown role %q, expected writer, editor or photographer", ErrInvalidAuthors, req.Role)
+		}
+		key := author.NameKey(req.Name)
+		if credited[key] {
+			return nil, fmt.Errorf("%w: %s is credited more than once", ErrInvalidAuthors, author.NormalizeName(req.Name))
+		}
+		credited[key] = true
+	}
 
-	return createdArticle, nil
+	bylines := make(Bylines, 0, len(reqs))
+	for _, req := range reqs {
+		authorObj, err := s.authorService.GetOrCreateAuthor(ctx, req.Name)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to get or create author for article")
+			return nil, fmt.Errorf("%w: failed to resolve author", err) // Wrap and return original error
+		}
+		role := req.Role
+		if role == "" {
+			role = RoleWriter
+		}
+		bylines = append(bylines, Byline{Author: *authorObj, Role: role})
+	}
+	return bylines, nil
 }
 
-func (s *articleService) GetArticles(ctx context.Context, filter *ArticleFilter) ([]*Article, error) {
+func (s *articleService) GetArticles(ctx context.Context, filter *ArticleFilter) (*ArticleList, error) {
 	// Set default pagination values
 	if filter.Page <= 0 {
 		filter.Page = 1
@@ -89,46 +172,491 @@ func (s *articleService) GetArticles(ctx context.Context, filter *ArticleFilter)
 		filter.Limit = 100
 	}
 
-	articles := []*Article{}
-	var err error
-
+	// Operators in the query become filters, what is left is the text to match
+	var query *parsedQuery
 	if filter.Query != "" {
-		logrus.WithField("query", filter.Query).Info("Performing Elasticsearch search")
-		searchResult, err := s.esClient.SearchDocuments(
-			ctx,
-			search.ArticleIndexName,
-			(elastic.NewMultiMatchQuery(filter.Query, "title", "body")),
-			(filter.Page-1)*filter.Limit,
-			filter.Limit,
-			false,
-			"published_at",
-		)
+		var err error
+		if query, err = parseQuery(filter.Query); err != nil {
+			return nil, err
+		}
+		if !query.narrow(filter) {
+			return &ArticleList{Data: []*Article{}, Page: filter.Page, Limit: filter.Limit}, nil
+		}
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = SortNewest
+		if filter.Query != "" {
+			filter.Sort = SortRelevance
+		}
+	case SortRelevance:
+		// Without a query every article matches equally, so fall back to the newest first
+		if filter.Query == "" {
+			filter.Sort = SortNewest
+		}
+	case SortNewest, SortOldest:
+	default:
+		return nil, ErrInvalidSort
+	}
+
+	for _, facet := range filter.Facets {
+		if facet != FacetAuthor && facet != FacetDate {
+			return nil, ErrInvalidFacet
+		}
+	}
+	switch filter.FacetInterval {
+	case "":
+		filter.FacetInterval = FacetIntervalMonth
+	case FacetIntervalDay, FacetIntervalWeek, FacetIntervalMonth:
+	default:
+		return nil, ErrInvalidFacet
+	}
+
+	var cursor *Cursor
+	if filter.Cursor != "" {
+		decoded, err := decodeCursor(filter.Cursor)
 		if err != nil {
-			logrus.WithError(err).Error("Elasticsearch search failed")
-			return nil, fmt.Errorf("failed to search articles: %w", err)
+			logrus.WithError(err).Warn("Failed to decode article cursor")
+			return nil, ErrInvalidCursor
+		}
+		if decoded.Sort != filter.Sort {
+			return nil, ErrInvalidCursor
+		}
+		cursor = decoded
+	}
+
+	// A cursor without search_after values was issued by the PostgreSQL fallback and continues there
+	if filter.Query != "" && (cursor == nil || len(cursor.SearchAfter) > 0) {
+		list, err := s.searchArticles(ctx, filter, query, cursor)
+		if !search.IsUnavailable(err) {
+			return list, err
+		}
+		if cursor != nil {
+			return nil, ErrSearchUnavailable
 		}
+		logrus.WithError(err).Warn("Elasticsearch unavailable, falling back to PostgreSQL full-text search")
+	}
+	return s.listArticles(ctx, filter, cursor)
+}
 
-		if searchResult.Hits.TotalHits.Value > 0 {
-			var articleIDs []string
-			for _, hit := range searchResult.Hits.Hits {
-				articleIDs = append(articleIDs, hit.Id)
-			}
-			// Fetch full articles from PostgreSQL using IDs from Elasticsearch
-			articles, err = s.repo.GetArticlesByID(ctx, filter, articleIDs)
-			if err != nil {
-				logrus.WithError(err).Error("Failed to retrieve full articles from DB after ES search")
-				return nil, fmt.Errorf("failed to retrieve articles details: %w", err)
+// searchArticles runs a full-text search in Elasticsearch and hydrates the hits from PostgreSQL,
+// keeping the order of the hits.
+func (s *articleService) searchArticles(ctx context.Context, filter *ArticleFilter, query *parsedQuery, cursor *Cursor) (*ArticleList, error) {
+	if cursor != nil && len(cursor.SearchAfter) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	opts := search.SearchOptions{
+		Size:        filter.Limit,
+		Sort:        searchSortFields(filter.Sort),
+		TrackScores: true,
+		Highlight: &search.HighlightOptions{
+			Fields:       []string{search.ArticleFieldTitle, search.ArticleFieldBody},
+			FragmentSize: s.searchCfg.HighlightFragmentSize,
+			Fragments:    s.searchCfg.HighlightFragments,
+			PreTag:       s.searchCfg.HighlightPreTag,
+			PostTag:      s.searchCfg.HighlightPostTag,
+		},
+	}
+	if len(filter.Facets) > 0 {
+		opts.Aggregations = facetAggregations(filter)
+	}
+	if cursor != nil {
+		// One extra hit tells whether there is a next page
+		opts.SearchAfter = cursor.SearchAfter
+		opts.Size = filter.Limit + 1
+	} else {
+		opts.From = (filter.Page - 1) * filter.Limit
+	}
+
+	logrus.WithField("query", filter.Query).Info("Performing Elasticsearch search")
+	searchResult, err := s.esClient.SearchDocuments(ctx, search.ArticleIndexName, searchQuery(filter, query), opts)
+	if err != nil {
+		logrus.WithError(err).Error("Elasticsearch search failed")
+		return nil, fmt.Errorf("failed to search articles: %w", err)
+	}
+
+	list := &ArticleList{
+		Data:    []*Article{},
+		Total:   searchResult.Total,
+		Limit:   filter.Limit,
+		Backend: s.searchCfg.Backend,
+	}
+	if len(filter.Facets) > 0 {
+		list.Facets = readFacets(searchResult.Aggregations)
+	}
+
+	hits := searchResult.Hits
+	if cursor != nil {
+		list.HasNext = len(hits) > filter.Limit
+		if list.HasNext {
+			hits = hits[:filter.Limit]
+		}
+	} else {
+		list.Page = filter.Page
+		list.HasNext = int64(filter.Page*filter.Limit) < list.Total
+	}
+	if list.HasNext && len(hits) > 0 {
+		list.NextCursor = encodeCursor(&Cursor{Sort: filter.Sort, SearchAfter: hits[len(hits)-1].Sort})
+	}
+
+	if list.Data, err = s.hydrateHits(ctx, hits); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// hydrateHits fetches the full articles of search hits from PostgreSQL, keeping the order of the hits.
+func (s *articleService) hydrateHits(ctx context.Context, hits []*search.Hit) ([]*Article, error) {
+	articles := []*Article{}
+	if len(hits) == 0 {
+		return articles, nil
+	}
+
+	var articleIDs []string
+	for _, hit := range hits {
+		articleIDs = append(articleIDs, hit.ID)
+	}
+	// Fetch full articles from PostgreSQL using IDs from Elasticsearch
+	found, err := s.repo.GetArticlesByID(ctx, articleIDs)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to retrieve full articles from DB after ES search")
+		return nil, fmt.Errorf("failed to retrieve articles details: %w", err)
+	}
+
+	byID := make(map[string]*Article, len(found))
+	for _, a := range found {
+		byID[a.ID] = a
+	}
+	// Hits whose article is gone from PostgreSQL (e.g. deleted before the index caught up) are skipped
+	for _, hit := range hits {
+		if a, ok := byID[hit.ID]; ok {
+			a.Score = hit.Score
+			if len(hit.Highlight) > 0 {
+				a.Highlights = hit.Highlight
 			}
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
+// sameAuthorBoost is how much more a related article sharing an author counts, when preferred.
+const sameAuthorBoost = 2
+
+// GetRelatedArticles returns up to limit articles similar to the given one, most similar first.
+// When preferSameAuthor is set, articles sharing an author rank higher but others are still included.
+// Related articles are only served by Elasticsearch; while it is down ErrSearchUnavailable is returned.
+func (s *articleService) GetRelatedArticles(ctx context.Context, id string, limit int, preferSameAuthor bool) (*RelatedArticles, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 20 {
+		limit = 20
+	}
+
+	source, err := s.GetArticleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The text comes from PostgreSQL, so an article that is not indexed yet still has related articles
+	query := &search.BoolQuery{
+		Must: []search.Query{&search.MoreLikeThisQuery{
+			Fields:        []string{search.ArticleFieldTitle, search.ArticleFieldBody},
+			LikeText:      source.Title + "\n\n" + source.Body,
+			MinTermFreq:   1,
+			MaxQueryTerms: 25,
+		}},
+		MustNot: []search.Query{&search.IDsQuery{IDs: []string{source.ID}}},
+	}
+	if preferSameAuthor {
+		names := []string{}
+		for _, byline := range source.Authors {
+			names = append(names, byline.Name)
+		}
+		query.Should = append(query.Should, &search.TermsQuery{
+			Field:  search.ArticleFieldAuthor,
+			Values: names,
+			Boost:  sameAuthorBoost,
+		})
+	}
+
+	result, err := s.esClient.SearchDocuments(ctx, search.ArticleIndexName, query, search.SearchOptions{
+		Size:   limit,
+		Source: []string{search.ArticleFieldID},
+	})
+	if err != nil {
+		if search.IsUnavailable(err) {
+			return nil, ErrSearchUnavailable
+		}
+		return nil, fmt.Errorf("failed to find related articles: %w", err)
+	}
+
+	related, err := s.hydrateHits(ctx, result.Hits)
+	if err != nil {
+		return nil, err
+	}
+	return &RelatedArticles{Data: related}, nil
+}
+
+// titleBoost weighs title matches above body matches.
+const titleBoost = 3
+
+// searchFields are the fields a query is matched against, with title matches boosted.
+var searchFields = []string{fmt.Sprintf("%s^%d", search.ArticleFieldTitle, titleBoost), search.ArticleFieldBody}
+
+// searchQuery builds the search query for a listing. Words tolerate typos, phrases must match
+// exactly and excluded words or phrases must not match. The filters are filter clauses of the query
+// itself, so that they apply before pagination, totals and facets, and do not affect scoring.
+func searchQuery(filter *ArticleFilter, parsed *parsedQuery) *search.BoolQuery {
+	query := &search.BoolQuery{}
+	if len(parsed.Words) > 0 {
+		query.Must = append(query.Must, &search.MatchQuery{Text: strings.Join(parsed.Words, " "), Fields: searchFields, Fuzzy: true})
+	}
+	for _, phrase := range parsed.Phrases {
+		query.Must = append(query.Must, &search.MatchQuery{Text: phrase, Fields: searchFields, Mode: search.MatchPhrase})
+	}
+	for _, excluded := range parsed.Excluded {
+		query.MustNot = append(query.MustNot, &search.MatchQuery{
+			Text:   excluded,
+			Fields: []string{search.ArticleFieldTitle, search.ArticleFieldBody},
+			Mode:   search.MatchPhrase,
+		})
+	}
+	if len(filter.Authors) > 0 {
+		query.Filter = append(query.Filter, &search.TermsQuery{Field: search.ArticleFieldAuthorKey, Values: nameKeys(filter.Authors)})
+	}
+	if len(filter.AuthorIDs) > 0 {
+		query.Filter = append(query.Filter, &search.TermsQuery{Field: search.ArticleFieldAuthorID, Values: filter.AuthorIDs})
+	}
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		query.Filter = append(query.Filter, &search.DateRangeQuery{Field: search.ArticleFieldCreatedAt, From: filter.From, To: filter.To})
+	}
+	return query
+}
+
+// Aggregation names of the search facets.
+const (
+	authorsAggregation = "authors"
+	datesAggregation   = "dates"
+)
+
+// facetAggregations builds the aggregations for the facets requested by the filter.
+func facetAggregations(filter *ArticleFilter) map[string]search.Aggregation {
+	aggs := map[string]search.Aggregation{}
+	for _, facet := range filter.Facets {
+		switch facet {
+		case FacetAuthor:
+			aggs[authorsAggregation] = &search.TermsAggregation{Field: search.ArticleFieldAuthor, Size: 10}
+		case FacetDate:
+			aggs[datesAggregation] = &search.DateHistogramAggregation{Field: search.ArticleFieldCreatedAt, Interval: filter.FacetInterval}
+		}
+	}
+	return aggs
+}
+
+// readFacets converts the aggregation results of a search into facets.
+func readFacets(aggs map[string][]search.Bucket) *Facets {
+	facets := &Facets{}
+	for _, bucket := range aggs[authorsAggregation] {
+		facets.Authors = append(facets.Authors, FacetBucket{Key: bucket.Key, Count: bucket.Count})
+	}
+	for _, bucket := range aggs[datesAggregation] {
+		facets.Dates = append(facets.Dates, FacetBucket{Key: bucket.Key, Count: bucket.Count})
+	}
+	return facets
+}
+
+// searchSortFields maps a listing sort to Elasticsearch sort fields.
+// The id is always the last field so that search_after positions are unique.
+func searchSortFields(sort string) []search.SortField {
+	switch sort {
+	case SortOldest:
+		return []search.SortField{{Field: search.ArticleFieldCreatedAt, Ascending: true}, {Field: search.ArticleFieldID, Ascending: true}}
+	case SortNewest:
+		return []search.SortField{{Field: search.ArticleFieldCreatedAt}, {Field: search.ArticleFieldID}}
+	default:
+		return []search.SortField{{Field: search.ScoreField}, {Field: search.ArticleFieldCreatedAt}, {Field: search.ArticleFieldID}}
+	}
+}
+
+// authorSuggestAggregation names the aggregation of the author suggestions.
+const authorSuggestAggregation = "author_suggest"
+
+// SuggestArticles returns the article titles and author names matching a partially typed prefix.
+// Suggestions are only served by Elasticsearch; when it is slow or down ErrSearchUnavailable is returned
+// rather than falling back, since a stale suggestion box is better than a slow one.
+func (s *articleService) SuggestArticles(ctx context.Context, prefix string, limit int) (*Suggestions, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > 10 {
+		limit = 10
+	}
+
+	// Elasticsearch returns what it found when its own timeout runs out, the deadline only
+	// cuts off a request that does not answer at all
+	ctx, cancel := context.WithTimeout(ctx, 2*s.searchCfg.SuggestTimeout)
+	defer cancel()
+
+	// Author names are matched over the whole index, not only the articles whose title matches
+	authors := &search.TermsAggregation{
+		Field:  search.ArticleFieldAuthor,
+		Size:   limit,
+		Filter: prefixQuery(prefix, search.ArticleFieldAuthor),
+		Global: true,
+	}
+
+	result, err := s.esClient.SearchDocuments(ctx, search.ArticleIndexName, prefixQuery(prefix, search.ArticleFieldTitle), search.SearchOptions{
+		Size:         limit,
+		Source:       []string{search.ArticleFieldID, search.ArticleFieldTitle},
+		Timeout:      s.searchCfg.SuggestTimeout,
+		Aggregations: map[string]search.Aggregation{authorSuggestAggregation: authors},
+	})
+	if err != nil {
+		if search.IsUnavailable(err) {
+			return nil, ErrSearchUnavailable
+		}
+		return nil, fmt.Errorf("failed to suggest articles: %w", err)
+	}
 
+	suggestions := &Suggestions{Articles: []ArticleSuggestion{}, Authors: []AuthorSuggestion{}}
+	for _, hit := range result.Hits {
+		var doc search.ArticleDocument
+		if err := json.Unmarshal(hit.Source, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode search document %s: %w", hit.ID, err)
+		}
+		suggestions.Articles = append(suggestions.Articles, ArticleSuggestion{ID: hit.ID, Title: doc.Title})
+	}
+	for _, bucket := range result.Aggregations[authorSuggestAggregation] {
+		suggestions.Authors = append(suggestions.Authors, AuthorSuggestion{Name: bucket.Key, Articles: bucket.Count})
+	}
+
+	return suggestions, nil
+}
+
+// prefixQuery matches documents whose field has words starting with the typed words.
+func prefixQuery(prefix, field string) search.Query {
+	return &search.MatchQuery{Text: prefix, Fields: []string{field}, Mode: search.MatchPrefix}
+}
+
+// listArticles lists articles straight from PostgreSQL, by page or by keyset cursor.
+// A query is matched with PostgreSQL full-text search.
+func (s *articleService) listArticles(ctx context.Context, filter *ArticleFilter, cursor *Cursor) (*ArticleList, error) {
+	logrus.WithField("filter", fmt.Sprintf("%#v", *filter)).Info("Performing PostgreSQL query for articles")
+
+	list := &ArticleList{Limit: filter.Limit, Backend: BackendPostgres}
+	var err error
+
+	if cursor != nil {
+		if cursor.ID == "" || filter.Sort == SortRelevance {
+			return nil, ErrInvalidCursor
+		}
+		list.Data, list.HasNext, err = s.repo.GetArticlesAfter(ctx, filter, cursor)
+		if err == nil {
+			list.Total, err = s.repo.CountArticles(ctx, filter)
 		}
 	} else {
-		logrus.WithField("filter", fmt.Sprintf("%#v", *filter)).Info("Performing PostgreSQL query for articles")
-		articles, err = s.repo.GetArticles(ctx, filter)
+		list.Page = filter.Page
+		list.Data, list.Total, err = s.repo.GetArticles(ctx, filter)
+		list.HasNext = int64(filter.Page*filter.Limit) < list.Total
+	}
+	if err != nil {
+		logrus.Errorf("Service failed to get articles from DB, err : %s", err)
+		return nil, fmt.Errorf("failed to get articles: %w", err)
+	}
+
+	// Every page hands out a cursor, so clients can switch from page to keyset mode at any point.
+	// Relevance ranks are not unique positions, so relevance pages are only reachable by number.
+	if list.HasNext && len(list.Data) > 0 && filter.Sort != SortRelevance {
+		last := list.Data[len(list.Data)-1]
+		list.NextCursor = encodeCursor(&Cursor{Sort: filter.Sort, CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return list, nil
+}
+
+// GetArticleByID retrieves a single article, returning ErrArticleNotFound if it does not exist.
+func (s *articleService) GetArticleByID(ctx context.Context, id string) (*Article, error) {
+	article, err := s.repo.GetArticleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArticleNotFound
+		}
+		logrus.WithError(err).WithField("article_id", id).Error("Service failed to get article from DB")
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+
+	return article, nil
+}
+
+// UpdateArticle replaces the title, body and authors of an existing article.
+func (s *articleService) UpdateArticle(ctx context.Context, id string, req *UpdateArticleRequest) (*Article, error) {
+	patch := &PatchArticleRequest{
+		Title:   &req.Title,
+		Body:    &req.Body,
+		Authors: req.Authors,
+	}
+	if req.Author != "" {
+		patch.Author = &req.Author
+	}
+	return s.PatchArticle(ctx, id, patch)
+}
+
+// PatchArticle applies the non-nil fields of req to an existing article.
+// An author or authors replace every byline of the article.
+func (s *articleService) PatchArticle(ctx context.Context, id string, req *PatchArticleRequest) (*Article, error) {
+	article, err := s.GetArticleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		article.Title = *req.Title
+	}
+	if req.Body != nil {
+		article.Body = *req.Body
+	}
+	// Re-crediting the sole writer leaves the bylines as they are
+	if req.Authors != nil || (req.Author != nil && !article.creditsOnly(*req.Author)) {
+		var name string
+		if req.Author != nil {
+			name = *req.Author
+		}
+		bylines, err := s.resolveBylines(ctx, name, req.Authors)
 		if err != nil {
-			logrus.Errorf("Service failed to get articles from DB, err : %s", err)
-			return nil, fmt.Errorf("failed to get articles: %w", err)
+			return nil, err
 		}
+		article.setBylines(bylines)
 	}
+	article.AuthorID = article.Author.ID
+	article.UpdatedAt = time.Now()
 
-	return articles, nil
+	updatedArticle, err := s.repo.UpdateArticle(ctx, article)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArticleNotFound
+		}
+		logrus.Errorf("Service failed to update article in DB, err : %s", err)
+		return nil, fmt.Errorf("failed to update article: %w", err)
+	}
+
+	return updatedArticle, nil
+}
+
+// DeleteArticle removes an article; the search index follows through the outbox.
+func (s *articleService) DeleteArticle(ctx context.Context, id string) error {
+	err := s.repo.DeleteArticle(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrArticleNotFound
+		}
+		logrus.Errorf("Service failed to delete article in DB, err : %s", err)
+		return fmt.Errorf("failed to delete article: %w", err)
+	}
+
+	return nil
 }
@@ -2,24 +2,42 @@ package article_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"kumparan-test/internal/article"
 	"kumparan-test/internal/article/mocks"
 	"kumparan-test/internal/author"
 	"kumparan-test/pkg/search"
 	"testing"
+	"time"
 
-	"github.com/olivere/elastic/v7"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+var defaultHighlight = &search.HighlightOptions{
+	Fields:       []string{"title", "body"},
+	FragmentSize: 150,
+	Fragments:    3,
+	PreTag:       "<em>",
+	PostTag:      "</em>",
+}
+
+var defaultSearchOptions = search.SearchOptions{
+	Size:        10,
+	Sort:        []search.SortField{{Field: "_score"}, {Field: "created_at"}, {Field: "id"}},
+	TrackScores: true,
+	Highlight:   defaultHighlight,
+}
+
 func TestPostArticle_Success(t *testing.T) {
 	mockRepo := new(mocks.MockRepo)
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	req := &article.CreateArticleRequest{
 		Title:  "Hello",
@@ -41,14 +59,89 @@ func TestPostArticle_Success(t *testing.T) {
 		return a.Title == "Hello" && a.AuthorID == "author-1"
 	})).Return(createdArticle, nil)
 
-	mockSearch.On("IndexDocument", mock.Anything, search.ArticleIndexName, "article-1", mock.Anything).Return(nil)
+	_, err := service.PostArticle(context.Background(), req)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockAuthor.AssertExpectations(t)
+}
+
+func TestPostArticle_CreditsAuthorsInOrder(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	req := &article.CreateArticleRequest{
+		Title: "Hello",
+		Body:  "World",
+		Authors: []article.BylineRequest{
+			{Name: "Matahari"},
+			{Name: "Sari", Role: article.RolePhotographer},
+			{Name: "Bara", Role: article.RoleEditor},
+		},
+	}
+
+	mockAuthor.On("GetOrCreateAuthor", mock.Anything, "Matahari").Return(&author.Author{ID: "author-1", Name: "Matahari"}, nil)
+	mockAuthor.On("GetOrCreateAuthor", mock.Anything, "Sari").Return(&author.Author{ID: "author-2", Name: "Sari"}, nil)
+	mockAuthor.On("GetOrCreateAuthor", mock.Anything, "Bara").Return(&author.Author{ID: "author-3", Name: "Bara"}, nil)
+
+	var created *article.Article
+	mockRepo.On("CreateArticle", mock.Anything, mock.MatchedBy(func(a *article.Article) bool {
+		created = a
+		return true
+	})).Return(&article.Article{ID: "article-1"}, nil)
 
 	_, err := service.PostArticle(context.Background(), req)
 
 	assert.NoError(t, err)
+	// The first byline is the lead author
+	assert.Equal(t, "author-1", created.AuthorID)
+	assert.Equal(t, "Matahari", created.Author.Name)
+	assert.Equal(t, article.Bylines{
+		{Author: author.Author{ID: "author-1", Name: "Matahari"}, Role: article.RoleWriter},
+		{Author: author.Author{ID: "author-2", Name: "Sari"}, Role: article.RolePhotographer},
+		{Author: author.Author{ID: "author-3", Name: "Bara"}, Role: article.RoleEditor},
+	}, created.Authors)
 	mockRepo.AssertExpectations(t)
 	mockAuthor.AssertExpectations(t)
-	mockSearch.AssertExpectations(t)
+}
+
+func TestPostArticle_InvalidAuthors(t *testing.T) {
+	tooMany := make([]article.BylineRequest, 11)
+	for i := range tooMany {
+		tooMany[i] = article.BylineRequest{Name: fmt.Sprintf("Author %d", i)}
+	}
+
+	tests := []struct {
+		name string
+		req  *article.CreateArticleRequest
+	}{
+		{"both author and authors", &article.CreateArticleRequest{Author: "Bara", Authors: []article.BylineRequest{{Name: "Sari"}}}},
+		{"no authors", &article.CreateArticleRequest{Authors: []article.BylineRequest{}}},
+		{"too many authors", &article.CreateArticleRequest{Authors: tooMany}},
+		{"nameless author", &article.CreateArticleRequest{Authors: []article.BylineRequest{{Name: "Bara"}, {Name: "  "}}}},
+		{"unknown role", &article.CreateArticleRequest{Authors: []article.BylineRequest{{Name: "Bara", Role: "illustrator"}}}},
+		{"author credited twice", &article.CreateArticleRequest{Authors: []article.BylineRequest{{Name: "Bara"}, {Name: " bara ", Role: article.RoleEditor}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockRepo)
+			mockAuthor := new(mocks.MockAuthorService)
+			mockSearch := new(mocks.MockSearchService)
+
+			service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+			_, err := service.PostArticle(context.Background(), tt.req)
+
+			assert.ErrorIs(t, err, article.ErrInvalidAuthors)
+			// Nothing is created when the list is rejected
+			mockAuthor.AssertNotCalled(t, "GetOrCreateAuthor", mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "CreateArticle", mock.Anything, mock.Anything)
+		})
+	}
 }
 
 func TestGetArticles_WithQuery_UsesElasticsearch(t *testing.T) {
@@ -56,7 +149,7 @@ func TestGetArticles_WithQuery_UsesElasticsearch(t *testing.T) {
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	filter := &article.ArticleFilter{
 		Query: "Go testing",
@@ -64,17 +157,18 @@ func TestGetArticles_WithQuery_UsesElasticsearch(t *testing.T) {
 		Limit: 10,
 	}
 
-	esHits := []*elastic.SearchHit{
-		{Id: "article-1"},
+	esHits := []*search.Hit{
+		{ID: "article-1"},
 	}
-	esResult := &elastic.SearchResult{
-		Hits: &elastic.SearchHits{TotalHits: &elastic.TotalHits{Value: 1}, Hits: esHits},
+	esResult := &search.SearchResult{
+		Total: 1,
+		Hits:  esHits,
 	}
 
-	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, 0, 10, false, "published_at").
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, defaultSearchOptions).
 		Return(esResult, nil)
 
-	mockRepo.On("GetArticlesByID", mock.Anything, filter, []string{"article-1"}).
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-1"}).
 		Return([]*article.Article{
 			{ID: "article-1", Title: "Test", Body: "Body"},
 		}, nil)
@@ -82,8 +176,10 @@ func TestGetArticles_WithQuery_UsesElasticsearch(t *testing.T) {
 	articles, err := service.GetArticles(context.Background(), filter)
 
 	assert.NoError(t, err)
-	assert.Len(t, articles, 1)
-	assert.Equal(t, "article-1", articles[0].ID)
+	assert.Len(t, articles.Data, 1)
+	assert.Equal(t, "article-1", articles.Data[0].ID)
+	assert.Equal(t, int64(1), articles.Total)
+	assert.False(t, articles.HasNext)
 
 	mockRepo.AssertExpectations(t)
 	mockSearch.AssertExpectations(t)
@@ -94,25 +190,29 @@ func TestGetArticles_NoQuery_UsesPostgreSQL(t *testing.T) {
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	filter := &article.ArticleFilter{
-		Query:  "",
-		Page:   -1,
-		Limit:  -10,
-		Author: "Bob",
+		Query:   "",
+		Page:    -1,
+		Limit:   -10,
+		Authors: []string{"Bob"},
 	}
 
 	mockRepo.On("GetArticles", mock.Anything, filter).
 		Return([]*article.Article{
 			{ID: "article-2", Title: "PostgreSQL", Body: "Article"},
-		}, nil)
+		}, int64(25), nil)
 
 	articles, err := service.GetArticles(context.Background(), filter)
 
 	assert.NoError(t, err)
-	assert.Len(t, articles, 1)
-	assert.Equal(t, "article-2", articles[0].ID)
+	assert.Len(t, articles.Data, 1)
+	assert.Equal(t, "article-2", articles.Data[0].ID)
+	assert.Equal(t, int64(25), articles.Total)
+	assert.Equal(t, 1, articles.Page)
+	assert.Equal(t, 10, articles.Limit)
+	assert.True(t, articles.HasNext)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -122,7 +222,7 @@ func TestPostArticle_AuthorServiceFails(t *testing.T) {
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	req := &article.CreateArticleRequest{Title: "X", Body: "Y", Author: "Fail"}
 
@@ -141,7 +241,7 @@ func TestPostArticle_DBCreateFails(t *testing.T) {
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	req := &article.CreateArticleRequest{Title: "Title", Body: "Body", Author: "Author"}
 	authorObj := &author.Author{ID: "auth1", Name: "Author"}
@@ -157,12 +257,12 @@ func TestPostArticle_DBCreateFails(t *testing.T) {
 	mockAuthor.AssertExpectations(t)
 }
 
-func TestPostArticle_ESIndexFailsButStillReturnsArticle(t *testing.T) {
+func TestPostArticle_DoesNotIndexSynchronously(t *testing.T) {
 	mockRepo := new(mocks.MockRepo)
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	req := &article.CreateArticleRequest{Title: "Title", Body: "Body", Author: "Matahari"}
 	authorObj := &author.Author{ID: "auth-1", Name: "Matahari"}
@@ -176,8 +276,6 @@ func TestPostArticle_ESIndexFailsButStillReturnsArticle(t *testing.T) {
 
 	mockAuthor.On("GetOrCreateAuthor", mock.Anything, "Matahari").Return(authorObj, nil)
 	mockRepo.On("CreateArticle", mock.Anything, mock.Anything).Return(articleObj, nil)
-	mockSearch.On("IndexDocument", mock.Anything, search.ArticleIndexName, "art-1", mock.Anything).
-		Return(fmt.Errorf("ES unavailable"))
 
 	result, err := service.PostArticle(context.Background(), req)
 
@@ -185,7 +283,7 @@ func TestPostArticle_ESIndexFailsButStillReturnsArticle(t *testing.T) {
 	assert.Equal(t, "art-1", result.ID)
 
 	mockRepo.AssertExpectations(t)
-	mockSearch.AssertExpectations(t)
+	mockSearch.AssertNotCalled(t, "IndexDocument", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestGetArticles_ESFails(t *testing.T) {
@@ -193,7 +291,7 @@ func TestGetArticles_ESFails(t *testing.T) {
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	filter := &article.ArticleFilter{
 		Query: "fail search",
@@ -201,8 +299,8 @@ func TestGetArticles_ESFails(t *testing.T) {
 		Limit: 10,
 	}
 
-	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, 0, 10, false, "published_at").
-		Return((*elastic.SearchResult)(nil), fmt.Errorf("es timeout"))
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, defaultSearchOptions).
+		Return((*search.SearchResult)(nil), fmt.Errorf("es timeout"))
 
 	_, err := service.GetArticles(context.Background(), filter)
 	assert.Error(t, err)
@@ -216,7 +314,7 @@ func TestGetArticles_ESWorks_ButDBFails(t *testing.T) {
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	filter := &article.ArticleFilter{
 		Query: "elastic",
@@ -224,15 +322,16 @@ func TestGetArticles_ESWorks_ButDBFails(t *testing.T) {
 		Limit: 10,
 	}
 
-	esHits := []*elastic.SearchHit{{Id: "id-1"}}
-	esResult := &elastic.SearchResult{
-		Hits: &elastic.SearchHits{TotalHits: &elastic.TotalHits{Value: 1}, Hits: esHits},
+	esHits := []*search.Hit{{ID: "id-1"}}
+	esResult := &search.SearchResult{
+		Total: 1,
+		Hits:  esHits,
 	}
 
-	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, 0, 10, false, "published_at").
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, defaultSearchOptions).
 		Return(esResult, nil)
 
-	mockRepo.On("GetArticlesByID", mock.Anything, filter, []string{"id-1"}).
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"id-1"}).
 		Return(([]*article.Article)(nil), fmt.Errorf("db failure"))
 
 	_, err := service.GetArticles(context.Background(), filter)
@@ -247,17 +346,17 @@ func TestGetArticles_PGOnly_Fails(t *testing.T) {
 	mockAuthor := new(mocks.MockAuthorService)
 	mockSearch := new(mocks.MockSearchService)
 
-	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch)
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
 
 	filter := &article.ArticleFilter{
-		Query:  "",
-		Page:   1,
-		Limit:  500,
-		Author: "Bob",
+		Query:   "",
+		Page:    1,
+		Limit:   500,
+		Authors: []string{"Bob"},
 	}
 
 	mockRepo.On("GetArticles", mock.Anything, filter).
-		Return(([]*article.Article)(nil), fmt.Errorf("pg error"))
+		Return(([]*article.Article)(nil), int64(0), fmt.Errorf("pg error"))
 
 	_, err := service.GetArticles(context.Background(), filter)
 	assert.Error(t, err)
@@ -265,3 +364,994 @@ func TestGetArticles_PGOnly_Fails(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetArticleByID_ReturnsArticle(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	mockRepo.On("GetArticleByID", mock.Anything, "article-1").
+		Return(&article.Article{ID: "article-1", Title: "Test"}, nil)
+
+	result, err := service.GetArticleByID(context.Background(), "article-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "article-1", result.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetArticleByID_NoRowsMapsToNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	mockRepo.On("GetArticleByID", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+	result, err := service.GetArticleByID(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, article.ErrArticleNotFound)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetArticleByID_DBFails(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	mockRepo.On("GetArticleByID", mock.Anything, "article-1").Return(nil, fmt.Errorf("db down"))
+
+	_, err := service.GetArticleByID(context.Background(), "article-1")
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, article.ErrArticleNotFound)
+	assert.Contains(t, err.Error(), "failed to get article")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateArticle_ChangesAuthor(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	existing := &article.Article{ID: "art-1", Title: "Old", Body: "Old body", Author: author.Author{ID: "auth-1", Name: "Bara"}}
+	newAuthor := &author.Author{ID: "auth-2", Name: "Biri"}
+
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(existing, nil)
+	mockAuthor.On("GetOrCreateAuthor", mock.Anything, "Biri").Return(newAuthor, nil)
+	mockRepo.On("UpdateArticle", mock.Anything, mock.MatchedBy(func(a *article.Article) bool {
+		return a.Title == "New" && a.Body == "New body" && a.AuthorID == "auth-2"
+	})).Return(existing, nil)
+
+	result, err := service.UpdateArticle(context.Background(), "art-1", &article.UpdateArticleRequest{
+		Title:  "New",
+		Body:   "New body",
+		Author: "Biri",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Biri", result.Author.Name)
+	mockRepo.AssertExpectations(t)
+	mockAuthor.AssertExpectations(t)
+}
+
+func TestPatchArticle_OnlyUpdatesProvidedFields(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	existing := &article.Article{ID: "art-1", Title: "Old", Body: "Body", Author: author.Author{ID: "auth-1", Name: "Bara"}}
+	newTitle := "Fixed typo"
+
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(existing, nil)
+	mockRepo.On("UpdateArticle", mock.Anything, mock.MatchedBy(func(a *article.Article) bool {
+		return a.Title == "Fixed typo" && a.Body == "Body" && a.AuthorID == "auth-1"
+	})).Return(existing, nil)
+
+	result, err := service.PatchArticle(context.Background(), "art-1", &article.PatchArticleRequest{Title: &newTitle})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Fixed typo", result.Title)
+	mockAuthor.AssertNotCalled(t, "GetOrCreateAuthor", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchArticle_ReplacesAuthors(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	bara := author.Author{ID: "auth-1", Name: "Bara"}
+	existing := &article.Article{ID: "art-1", Title: "Old", Body: "Body", Author: bara,
+		Authors: article.Bylines{{Author: bara, Role: article.RoleWriter}}}
+
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(existing, nil)
+	mockAuthor.On("GetOrCreateAuthor", mock.Anything, "Sari").Return(&author.Author{ID: "auth-2", Name: "Sari"}, nil)
+	mockAuthor.On("GetOrCreateAuthor", mock.Anything, "Bara").Return(&bara, nil)
+	mockRepo.On("UpdateArticle", mock.Anything, mock.MatchedBy(func(a *article.Article) bool {
+		return a.Title == "Old" && a.AuthorID == "auth-2" && len(a.Authors) == 2 &&
+			a.Authors[1].ID == "auth-1" && a.Authors[1].Role == article.RoleEditor
+	})).Return(existing, nil)
+
+	_, err := service.PatchArticle(context.Background(), "art-1", &article.PatchArticleRequest{
+		Authors: []article.BylineRequest{{Name: "Sari"}, {Name: "Bara", Role: article.RoleEditor}},
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockAuthor.AssertExpectations(t)
+}
+
+func TestPatchArticle_SameSoleAuthorKeepsBylines(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	bara := author.Author{ID: "auth-1", Name: "Bara"}
+	existing := &article.Article{ID: "art-1", Title: "Old", Body: "Body", Author: bara,
+		Authors: article.Bylines{{Author: bara, Role: article.RoleWriter}}}
+	name := "Bara"
+
+	mockRepo.On("GetArticleByID", mock.Anything, "art-1").Return(existing, nil)
+	mockRepo.On("UpdateArticle", mock.Anything, mock.MatchedBy(func(a *article.Article) bool {
+		return a.AuthorID == "auth-1" && len(a.Authors) == 1
+	})).Return(existing, nil)
+
+	_, err := service.PatchArticle(context.Background(), "art-1", &article.PatchArticleRequest{Author: &name})
+
+	assert.NoError(t, err)
+	mockAuthor.AssertNotCalled(t, "GetOrCreateAuthor", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchArticle_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	newTitle := "T"
+	mockRepo.On("GetArticleByID", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+	_, err := service.PatchArticle(context.Background(), "missing", &article.PatchArticleRequest{Title: &newTitle})
+
+	assert.ErrorIs(t, err, article.ErrArticleNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteArticle_Deletes(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	mockRepo.On("DeleteArticle", mock.Anything, "art-1").Return(nil)
+
+	err := service.DeleteArticle(context.Background(), "art-1")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteArticle_NoRowsMapsToNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	mockRepo.On("DeleteArticle", mock.Anything, "missing").Return(sql.ErrNoRows)
+
+	err := service.DeleteArticle(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, article.ErrArticleNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetArticles_CursorRoundTrip_UsesKeyset(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC)
+	firstPage := &article.ArticleFilter{Page: 1, Limit: 1}
+	mockRepo.On("GetArticles", mock.Anything, firstPage).
+		Return([]*article.Article{{ID: "article-2", CreatedAt: createdAt}}, int64(2), nil)
+
+	first, err := service.GetArticles(context.Background(), firstPage)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, first.NextCursor)
+
+	nextPage := &article.ArticleFilter{Limit: 1, Cursor: first.NextCursor}
+	mockRepo.On("GetArticlesAfter", mock.Anything, nextPage, mock.MatchedBy(func(c *article.Cursor) bool {
+		return c.ID == "article-2" && c.CreatedAt.Equal(createdAt)
+	})).Return([]*article.Article{{ID: "article-1"}}, false, nil)
+	mockRepo.On("CountArticles", mock.Anything, nextPage).Return(int64(2), nil)
+
+	second, err := service.GetArticles(context.Background(), nextPage)
+
+	assert.NoError(t, err)
+	assert.Len(t, second.Data, 1)
+	assert.Equal(t, 0, second.Page)
+	assert.Equal(t, int64(2), second.Total)
+	assert.False(t, second.HasNext)
+	assert.Empty(t, second.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetArticles_CursorWithQuery_UsesSearchAfter(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	firstPage := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 1}
+	firstResult := &search.SearchResult{
+		Total: 3,
+		Hits:  []*search.Hit{{ID: "article-3", Sort: []interface{}{json.Number("1700000000000"), "article-3"}}},
+	}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return opts.SearchAfter == nil
+	})).Return(firstResult, nil).Once()
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-3"}).
+		Return([]*article.Article{{ID: "article-3"}}, nil)
+
+	first, err := service.GetArticles(context.Background(), firstPage)
+	assert.NoError(t, err)
+	assert.True(t, first.HasNext)
+
+	nextPage := &article.ArticleFilter{Query: "banjir", Limit: 1, Cursor: first.NextCursor}
+	nextResult := &search.SearchResult{
+		Total: 3,
+		Hits: []*search.Hit{
+			{ID: "article-2", Sort: []interface{}{json.Number("1600000000000"), "article-2"}},
+			{ID: "article-1", Sort: []interface{}{json.Number("1500000000000"), "article-1"}},
+		},
+	}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return len(opts.SearchAfter) == 2 &&
+			opts.SearchAfter[0] == json.Number("1700000000000") &&
+			opts.SearchAfter[1] == "article-3" &&
+			opts.Size == 2
+	})).Return(nextResult, nil).Once()
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-2"}).
+		Return([]*article.Article{{ID: "article-2"}}, nil)
+
+	second, err := service.GetArticles(context.Background(), nextPage)
+
+	assert.NoError(t, err)
+	assert.Len(t, second.Data, 1)
+	assert.True(t, second.HasNext)
+	assert.NotEmpty(t, second.NextCursor)
+	mockRepo.AssertExpectations(t)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestGetArticles_InvalidCursor(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	_, err := service.GetArticles(context.Background(), &article.ArticleFilter{Cursor: "%%%not-base64"})
+
+	assert.ErrorIs(t, err, article.ErrInvalidCursor)
+	mockRepo.AssertNotCalled(t, "GetArticlesAfter", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetArticles_WithQuery_KeepsHitOrderAndScores(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 10}
+	best, worse := 3.5, 1.25
+	esResult := &search.SearchResult{
+		Total: 3,
+		Hits: []*search.Hit{
+			{ID: "article-old", Score: &best},
+			{ID: "article-gone", Score: &best},
+			{ID: "article-new", Score: &worse},
+		},
+	}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, defaultSearchOptions).
+		Return(esResult, nil)
+	// PostgreSQL returns rows in its own order and no longer has one of the hits
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-old", "article-gone", "article-new"}).
+		Return([]*article.Article{{ID: "article-new"}, {ID: "article-old"}}, nil)
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, article.SortRelevance, filter.Sort)
+	if assert.Len(t, list.Data, 2) {
+		assert.Equal(t, "article-old", list.Data[0].ID)
+		assert.Equal(t, 3.5, *list.Data[0].Score)
+		assert.Equal(t, "article-new", list.Data[1].ID)
+		assert.Equal(t, 1.25, *list.Data[1].Score)
+	}
+}
+
+func TestGetArticles_WithQuery_SortsByDate(t *testing.T) {
+	tests := []struct {
+		sort     string
+		expected []search.SortField
+	}{
+		{article.SortNewest, []search.SortField{{Field: "created_at"}, {Field: "id"}}},
+		{article.SortOldest, []search.SortField{{Field: "created_at", Ascending: true}, {Field: "id", Ascending: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			mockRepo := new(mocks.MockRepo)
+			mockAuthor := new(mocks.MockAuthorService)
+			mockSearch := new(mocks.MockSearchService)
+
+			service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+			filter := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 10, Sort: tt.sort}
+			opts := search.SearchOptions{Size: 10, Sort: tt.expected, TrackScores: true, Highlight: defaultHighlight}
+			mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, opts).
+				Return(&search.SearchResult{}, nil)
+
+			_, err := service.GetArticles(context.Background(), filter)
+
+			assert.NoError(t, err)
+			mockSearch.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetArticles_NoQuery_RelevanceFallsBackToNewest(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Page: 1, Limit: 10, Sort: article.SortRelevance}
+	mockRepo.On("GetArticles", mock.Anything, filter).Return([]*article.Article{}, int64(0), nil)
+
+	_, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, article.SortNewest, filter.Sort)
+	mockSearch.AssertNotCalled(t, "SearchDocuments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetArticles_InvalidSort(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	_, err := service.GetArticles(context.Background(), &article.ArticleFilter{Sort: "popular"})
+
+	assert.ErrorIs(t, err, article.ErrInvalidSort)
+}
+
+func TestGetArticles_CursorFromOtherSort(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	firstPage := &article.ArticleFilter{Page: 1, Limit: 1}
+	mockRepo.On("GetArticles", mock.Anything, firstPage).
+		Return([]*article.Article{{ID: "article-2", CreatedAt: time.Now()}}, int64(2), nil)
+
+	first, err := service.GetArticles(context.Background(), firstPage)
+	assert.NoError(t, err)
+
+	_, err = service.GetArticles(context.Background(), &article.ArticleFilter{Limit: 1, Cursor: first.NextCursor, Sort: article.SortOldest})
+
+	assert.ErrorIs(t, err, article.ErrInvalidCursor)
+	mockRepo.AssertNotCalled(t, "GetArticlesAfter", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetArticles_SearchUnavailable_FallsBackToPostgreSQL(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 10}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, defaultSearchOptions).
+		Return((*search.SearchResult)(nil), search.ErrCircuitOpen)
+	mockRepo.On("GetArticles", mock.Anything, filter).
+		Return([]*article.Article{{ID: "article-1"}}, int64(1), nil)
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, article.BackendPostgres, list.Backend)
+	assert.Len(t, list.Data, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetArticles_SearchRejected_DoesNotFallBack(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 10}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, defaultSearchOptions).
+		Return((*search.SearchResult)(nil), errors.New("failed to parse query"))
+
+	_, err := service.GetArticles(context.Background(), filter)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "GetArticles", mock.Anything, mock.Anything)
+}
+
+func TestGetArticles_SearchUnavailable_ElasticsearchCursor(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	firstPage := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 1}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return opts.SearchAfter == nil
+	})).Return(&search.SearchResult{
+		Total: 2,
+		Hits:  []*search.Hit{{ID: "article-2", Sort: []interface{}{json.Number("1.5"), "article-2"}}},
+	}, nil).Once()
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-2"}).
+		Return([]*article.Article{{ID: "article-2"}}, nil)
+
+	first, err := service.GetArticles(context.Background(), firstPage)
+	assert.NoError(t, err)
+	assert.Equal(t, article.BackendElasticsearch, first.Backend)
+
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.Anything).
+		Return((*search.SearchResult)(nil), search.ErrCircuitOpen)
+
+	_, err = service.GetArticles(context.Background(), &article.ArticleFilter{Query: "banjir", Limit: 1, Cursor: first.NextCursor})
+
+	assert.ErrorIs(t, err, article.ErrSearchUnavailable)
+}
+
+func TestGetArticles_PostgresSearchCursor_StaysOnPostgreSQL(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	// First page falls back while Elasticsearch is down
+	firstPage := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 1, Sort: article.SortNewest}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.Anything).
+		Return((*search.SearchResult)(nil), search.ErrCircuitOpen).Once()
+	mockRepo.On("GetArticles", mock.Anything, firstPage).
+		Return([]*article.Article{{ID: "article-2", CreatedAt: time.Now()}}, int64(2), nil)
+
+	first, err := service.GetArticles(context.Background(), firstPage)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, first.NextCursor)
+
+	// Its cursor continues on PostgreSQL even though Elasticsearch would answer now
+	nextPage := &article.ArticleFilter{Query: "banjir", Limit: 1, Sort: article.SortNewest, Cursor: first.NextCursor}
+	mockRepo.On("GetArticlesAfter", mock.Anything, nextPage, mock.Anything).
+		Return([]*article.Article{{ID: "article-1"}}, false, nil)
+	mockRepo.On("CountArticles", mock.Anything, nextPage).Return(int64(2), nil)
+
+	second, err := service.GetArticles(context.Background(), nextPage)
+
+	assert.NoError(t, err)
+	assert.Equal(t, article.BackendPostgres, second.Backend)
+	mockSearch.AssertNumberOfCalls(t, "SearchDocuments", 1)
+}
+
+func TestGetArticles_WithQuery_ReturnsHighlights(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{
+		HighlightFragmentSize: 80,
+		HighlightPreTag:       "<mark>",
+		HighlightPostTag:      "</mark>",
+	})
+
+	filter := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 10}
+	esResult := &search.SearchResult{
+		Total: 2,
+		Hits: []*search.Hit{
+			{ID: "article-1", Highlight: map[string][]string{"body": {"saat <mark>banjir</mark> datang"}}},
+			{ID: "article-2"},
+		},
+	}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return opts.Highlight != nil &&
+			opts.Highlight.FragmentSize == 80 &&
+			opts.Highlight.PreTag == "<mark>" &&
+			opts.Highlight.PostTag == "</mark>"
+	})).Return(esResult, nil)
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-1", "article-2"}).
+		Return([]*article.Article{{ID: "article-1"}, {ID: "article-2"}}, nil)
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"saat <mark>banjir</mark> datang"}, list.Data[0].Highlights["body"])
+	assert.Nil(t, list.Data[1].Highlights)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestGetArticles_WithFacets(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 10, Facets: []string{"author", "date"}, FacetInterval: "week"}
+	esResult := &search.SearchResult{
+		Aggregations: map[string][]search.Bucket{
+			"authors": {{Key: "Bara", Count: 3}, {Key: "Sari", Count: 1}},
+			"dates":   {{Key: "2025-01-06", Count: 4}},
+		},
+	}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return len(opts.Aggregations) == 2 &&
+			assert.ObjectsAreEqual(&search.DateHistogramAggregation{Field: "created_at", Interval: "week"}, opts.Aggregations["dates"])
+	})).Return(esResult, nil)
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &article.Facets{
+		Authors: []article.FacetBucket{{Key: "Bara", Count: 3}, {Key: "Sari", Count: 1}},
+		Dates:   []article.FacetBucket{{Key: "2025-01-06", Count: 4}},
+	}, list.Facets)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestGetArticles_WithoutFacets_NoAggregations(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Query: "banjir", Page: 1, Limit: 10}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, defaultSearchOptions).
+		Return(&search.SearchResult{}, nil)
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Nil(t, list.Facets)
+}
+
+func TestGetArticles_InvalidFacet(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	_, err := service.GetArticles(context.Background(), &article.ArticleFilter{Query: "banjir", Facets: []string{"tag"}})
+	assert.ErrorIs(t, err, article.ErrInvalidFacet)
+
+	_, err = service.GetArticles(context.Background(), &article.ArticleFilter{Query: "banjir", Facets: []string{"date"}, FacetInterval: "year"})
+	assert.ErrorIs(t, err, article.ErrInvalidFacet)
+}
+
+func TestGetArticles_WithQuery_FiltersInSearchQuery(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	filter := &article.ArticleFilter{
+		Query:     "banjir",
+		Page:      1,
+		Limit:     10,
+		Authors:   []string{"Bara", "Sari"},
+		AuthorIDs: []string{"auth-1"},
+		From:      from,
+		To:        to,
+	}
+
+	var query *search.BoolQuery
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.MatchedBy(func(q *search.BoolQuery) bool {
+		query = q
+		return true
+	}), defaultSearchOptions).
+		Return(&search.SearchResult{}, nil)
+
+	_, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, query.Must)
+	assert.ElementsMatch(t, []search.Query{
+		&search.TermsQuery{Field: "author_key", Values: []string{"bara", "sari"}},
+		&search.TermsQuery{Field: "author_id", Values: []string{"auth-1"}},
+		&search.DateRangeQuery{Field: "created_at", From: from, To: to},
+	}, query.Filter)
+	mockRepo.AssertNotCalled(t, "GetArticlesByID", mock.Anything, mock.Anything)
+}
+
+// authorFilteredQuery matches a search query that filters on exactly the given author name keys.
+func authorFilteredQuery(keys ...string) interface{} {
+	want := &search.TermsQuery{Field: "author_key", Values: keys}
+	return mock.MatchedBy(func(q *search.BoolQuery) bool {
+		for _, clause := range q.Filter {
+			if assert.ObjectsAreEqual(want, clause) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func TestGetArticles_WithQuery_AuthorFilterIgnoresCaseAndSpacing(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.MockRepo)
+	searchService := search.NewMemorySearchService()
+
+	budi := author.Author{ID: "auth-1", Name: "Budi Santoso"}
+	sari := author.Author{ID: "auth-2", Name: "Sari"}
+	articles := []*article.Article{
+		{ID: "a1", Title: "Banjir Jakarta", Author: budi, Authors: article.Bylines{{Author: budi, Role: article.RoleWriter}}},
+		{ID: "a2", Title: "Banjir Bogor", Author: sari, Authors: article.Bylines{{Author: sari, Role: article.RoleWriter}}},
+	}
+	mockRepo.On("GetArticlesAfter", mock.Anything, mock.Anything, (*article.Cursor)(nil)).Return(articles, false, nil)
+	mockRepo.On("GetDeletedArticleIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
+	mockRepo.On("GetArticlesChangedSince", mock.Anything, mock.Anything, "", 500).Return([]*article.Article{}, nil)
+	_, err := article.NewReindexer(mockRepo, searchService, 0).Run(ctx, true)
+	assert.NoError(t, err)
+
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"a1"}).Return(articles[:1], nil)
+	service := article.NewArticleService(mockRepo, new(mocks.MockAuthorService), searchService, article.SearchConfig{})
+
+	for _, name := range []string{"budi santoso", "Budi  Santoso ", "BUDI SANTOSO"} {
+		list, err := service.GetArticles(ctx, &article.ArticleFilter{Query: "banjir", Authors: []string{name}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), list.Total, name)
+		if assert.Len(t, list.Data, 1, name) {
+			assert.Equal(t, "a1", list.Data[0].ID)
+		}
+	}
+}
+
+func TestGetArticles_QueryAndAuthor_ReturnsFullPagesAndTotal(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	// Only Bara's articles match, so Elasticsearch counts and pages them alone
+	filter := &article.ArticleFilter{Query: "banjir", Authors: []string{"Bara"}, Page: 2, Limit: 2}
+	esResult := &search.SearchResult{
+		Total: 5,
+		Hits:  []*search.Hit{{ID: "article-3"}, {ID: "article-4"}},
+	}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, authorFilteredQuery("bara"), mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return opts.From == 2 && opts.Size == 2
+	})).Return(esResult, nil)
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-3", "article-4"}).
+		Return([]*article.Article{
+			{ID: "article-3", Author: author.Author{Name: "Bara"}},
+			{ID: "article-4", Author: author.Author{Name: "Bara"}},
+		}, nil)
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, list.Data, 2)
+	assert.Equal(t, int64(5), list.Total)
+	assert.True(t, list.HasNext)
+	mockRepo.AssertExpectations(t)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestGetArticles_QueryAndAuthor_CursorKeepsFilter(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	firstPage := &article.ArticleFilter{Query: "banjir", Authors: []string{"Bara"}, Page: 1, Limit: 1}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, authorFilteredQuery("bara"), mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return opts.SearchAfter == nil
+	})).Return(&search.SearchResult{
+		Total: 2,
+		Hits:  []*search.Hit{{ID: "article-2", Sort: []interface{}{json.Number("1"), json.Number("1700000000000"), "article-2"}}},
+	}, nil).Once()
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-2"}).
+		Return([]*article.Article{{ID: "article-2"}}, nil)
+
+	first, err := service.GetArticles(context.Background(), firstPage)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), first.Total)
+
+	nextPage := &article.ArticleFilter{Query: "banjir", Authors: []string{"Bara"}, Limit: 1, Cursor: first.NextCursor}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, authorFilteredQuery("bara"), mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return len(opts.SearchAfter) == 3
+	})).Return(&search.SearchResult{
+		Total: 2,
+		Hits:  []*search.Hit{{ID: "article-1", Sort: []interface{}{json.Number("1"), json.Number("1600000000000"), "article-1"}}},
+	}, nil).Once()
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-1"}).
+		Return([]*article.Article{{ID: "article-1"}}, nil)
+
+	second, err := service.GetArticles(context.Background(), nextPage)
+
+	assert.NoError(t, err)
+	assert.Len(t, second.Data, 1)
+	assert.False(t, second.HasNext)
+	mockSearch.AssertExpectations(t)
+}
+
+func TestSuggestArticles_ReturnsTitlesAndAuthors(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	esResult := &search.SearchResult{
+		Total: 1,
+		Hits:  []*search.Hit{{ID: "article-1", Source: json.RawMessage(`{"id":"article-1","title":"Banjir Jakarta"}`)}},
+		Aggregations: map[string][]search.Bucket{
+			"author_suggest": {{Key: "Bara", Count: 3}},
+		},
+	}
+	prefix := func(field string) search.Query {
+		return &search.MatchQuery{Text: "ban", Fields: []string{field}, Mode: search.MatchPrefix}
+	}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, prefix("title"), mock.MatchedBy(func(opts search.SearchOptions) bool {
+		return opts.Size == 10 && opts.Timeout == 200*time.Millisecond &&
+			assert.ObjectsAreEqual(&search.TermsAggregation{Field: "author", Size: 10, Filter: prefix("author"), Global: true}, opts.Aggregations["author_suggest"])
+	})).Return(esResult, nil)
+
+	suggestions, err := service.SuggestArticles(context.Background(), "ban", 50)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []article.ArticleSuggestion{{ID: "article-1", Title: "Banjir Jakarta"}}, suggestions.Articles)
+	assert.Equal(t, []article.AuthorSuggestion{{Name: "Bara", Articles: 3}}, suggestions.Authors)
+	mockSearch.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetArticlesByID", mock.Anything, mock.Anything)
+}
+
+func TestSuggestArticles_SearchUnavailable(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.Anything).
+		Return((*search.SearchResult)(nil), search.ErrCircuitOpen)
+
+	_, err := service.SuggestArticles(context.Background(), "ban", 0)
+
+	assert.ErrorIs(t, err, article.ErrSearchUnavailable)
+}
+
+func TestGetArticles_QuerySyntax_BuildsBoolQuery(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{
+		Query: `banjr "curah hujan" -bogor -"kota hujan" author:"Bara Ali" after:2025-01-01 before:2025-02-01`,
+		Page:  1,
+		Limit: 10,
+	}
+	var query *search.BoolQuery
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.MatchedBy(func(q *search.BoolQuery) bool {
+		query = q
+		return true
+	}), defaultSearchOptions).
+		Return(&search.SearchResult{}, nil)
+
+	_, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []search.Query{
+		&search.MatchQuery{Text: "banjr", Fields: []string{"title^3", "body"}, Fuzzy: true},
+		&search.MatchQuery{Text: "curah hujan", Fields: []string{"title^3", "body"}, Mode: search.MatchPhrase},
+	}, query.Must)
+	assert.Equal(t, []search.Query{
+		&search.MatchQuery{Text: "bogor", Fields: []string{"title", "body"}, Mode: search.MatchPhrase},
+		&search.MatchQuery{Text: "kota hujan", Fields: []string{"title", "body"}, Mode: search.MatchPhrase},
+	}, query.MustNot)
+	assert.ElementsMatch(t, []search.Query{
+		&search.TermsQuery{Field: "author_key", Values: []string{"bara ali"}},
+		&search.DateRangeQuery{
+			Field: "created_at",
+			From:  time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			To:    time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}, query.Filter)
+}
+
+func TestGetArticles_QueryOperatorsOnly_ListsFromPostgreSQL(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Query: "AUTHOR:Bara before:2025-02-01", Page: 1, Limit: 10}
+	mockRepo.On("GetArticles", mock.Anything, &article.ArticleFilter{
+		Page:    1,
+		Limit:   10,
+		Sort:    article.SortNewest,
+		Authors: []string{"Bara"},
+		To:      time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+
+		FacetInterval: article.FacetIntervalMonth,
+	}).Return([]*article.Article{{ID: "article-1"}}, int64(1), nil)
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, list.Data, 1)
+	mockRepo.AssertExpectations(t)
+	mockSearch.AssertNotCalled(t, "SearchDocuments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetArticles_QuerySyntax_FallsBackToWebSearchText(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Query: `banjir -"kota hujan" author:Bara`, Page: 1, Limit: 10}
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, defaultSearchOptions).
+		Return((*search.SearchResult)(nil), search.ErrCircuitOpen)
+	mockRepo.On("GetArticles", mock.Anything, mock.MatchedBy(func(f *article.ArticleFilter) bool {
+		return f.Query == `banjir -"kota hujan"` && len(f.Authors) == 1 && f.Authors[0] == "Bara"
+	})).Return([]*article.Article{}, int64(0), nil)
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, article.BackendPostgres, list.Backend)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetArticles_QueryAuthorOutsideAuthorFilter_ReturnsNothing(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	filter := &article.ArticleFilter{Query: "banjir author:Sari", Authors: []string{"Bara"}, Page: 1, Limit: 10}
+
+	list, err := service.GetArticles(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Empty(t, list.Data)
+	assert.Zero(t, list.Total)
+	mockSearch.AssertNotCalled(t, "SearchDocuments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetArticles", mock.Anything, mock.Anything)
+}
+
+func TestGetArticles_InvalidQuery(t *testing.T) {
+	service := article.NewArticleService(new(mocks.MockRepo), new(mocks.MockAuthorService), new(mocks.MockSearchService), article.SearchConfig{})
+
+	for _, q := range []string{`banjir "curah hujan`, "banjir author:", "before:kemarin", "banjir -"} {
+		_, err := service.GetArticles(context.Background(), &article.ArticleFilter{Query: q})
+		assert.ErrorIs(t, err, article.ErrInvalidQuery, q)
+	}
+}
+
+func TestGetRelatedArticles_ExcludesSourceAndPrefersAuthor(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockAuthor := new(mocks.MockAuthorService)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, mockAuthor, mockSearch, article.SearchConfig{})
+
+	mockRepo.On("GetArticleByID", mock.Anything, "article-1").
+		Return(&article.Article{
+			ID: "article-1", Title: "Banjir Jakarta", Body: "Curah hujan tinggi",
+			Author: author.Author{Name: "Bara"},
+			Authors: article.Bylines{
+				{Author: author.Author{Name: "Bara"}, Role: article.RoleWriter},
+				{Author: author.Author{Name: "Sari"}, Role: article.RolePhotographer},
+			},
+		}, nil)
+
+	var query *search.BoolQuery
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.MatchedBy(func(q *search.BoolQuery) bool {
+		query = q
+		return true
+	}), search.SearchOptions{Size: 3, Source: []string{"id"}}).
+		Return(&search.SearchResult{
+			Total: 2,
+			Hits:  []*search.Hit{{ID: "article-3"}, {ID: "article-2"}},
+		}, nil)
+	mockRepo.On("GetArticlesByID", mock.Anything, []string{"article-3", "article-2"}).
+		Return([]*article.Article{{ID: "article-2"}, {ID: "article-3"}}, nil)
+
+	related, err := service.GetRelatedArticles(context.Background(), "article-1", 3, true)
+
+	assert.NoError(t, err)
+	assert.Len(t, related.Data, 2)
+	assert.Equal(t, "article-3", related.Data[0].ID)
+	assert.Equal(t, "article-2", related.Data[1].ID)
+
+	assert.Equal(t, []search.Query{&search.MoreLikeThisQuery{
+		Fields:        []string{"title", "body"},
+		LikeText:      "Banjir Jakarta\n\nCurah hujan tinggi",
+		MinTermFreq:   1,
+		MaxQueryTerms: 25,
+	}}, query.Must)
+	assert.Equal(t, []search.Query{&search.IDsQuery{IDs: []string{"article-1"}}}, query.MustNot)
+	assert.Equal(t, []search.Query{&search.TermsQuery{Field: "author", Values: []string{"Bara", "Sari"}, Boost: 2}}, query.Should)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRelatedArticles_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, new(mocks.MockAuthorService), mockSearch, article.SearchConfig{})
+
+	mockRepo.On("GetArticleByID", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+	_, err := service.GetRelatedArticles(context.Background(), "missing", 0, false)
+
+	assert.ErrorIs(t, err, article.ErrArticleNotFound)
+	mockSearch.AssertNotCalled(t, "SearchDocuments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRelatedArticles_SearchUnavailable(t *testing.T) {
+	mockRepo := new(mocks.MockRepo)
+	mockSearch := new(mocks.MockSearchService)
+
+	service := article.NewArticleService(mockRepo, new(mocks.MockAuthorService), mockSearch, article.SearchConfig{})
+
+	mockRepo.On("GetArticleByID", mock.Anything, "article-1").Return(&article.Article{ID: "article-1"}, nil)
+	mockSearch.On("SearchDocuments", mock.Anything, search.ArticleIndexName, mock.Anything, mock.Anything).
+		Return((*search.SearchResult)(nil), search.ErrCircuitOpen)
+
+	_, err := service.GetRelatedArticles(context.Background(), "article-1", 0, false)
+
+	assert.ErrorIs(t, err, article.ErrSearchUnavailable)
+}
@@ -27,3 +27,61 @@ func (m *MockAuthorRepo) CreateAuthor(ctx context.Context, a *author.Author) (*a
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockAuthorRepo) GetAuthors(ctx context.Context, limit, offset int) ([]*author.Author, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if a := args.Get(0); a != nil {
+		return a.([]*author.Author), args.Get(1).(int64), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockAuthorRepo) GetAuthorByID(ctx context.Context, id string) (*author.Author, error) {
+	args := m.Called(ctx, id)
+	if a := args.Get(0); a != nil {
+		return a.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorRepo) UpdateAuthor(ctx context.Context, a *author.Author) (*author.Author, error) {
+	args := m.Called(ctx, a)
+	if a := args.Get(0); a != nil {
+		return a.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorRepo) DeleteAuthor(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthorRepo) UpsertAuthor(ctx context.Context, name string) (*author.Author, error) {
+	args := m.Called(ctx, name)
+	if a := args.Get(0); a != nil {
+		return a.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorRepo) MergeAuthors(ctx context.Context, canonicalID string, duplicateIDs []string) (int64, error) {
+	args := m.Called(ctx, canonicalID, duplicateIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuthorRepo) GetAuthorBySlug(ctx context.Context, slug string) (*author.Author, error) {
+	args := m.Called(ctx, slug)
+	if a := args.Get(0); a != nil {
+		return a.(*author.Author), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthorRepo) GetStoredNames(ctx context.Context) ([]*author.StoredName, error) {
+	args := m.Called(ctx)
+	if names := args.Get(0); names != nil {
+		return names.([]*author.StoredName), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
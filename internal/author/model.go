@@ -1,6 +1,102 @@
 package author
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventAuthorUpdated is the outbox event emitted when an author is renamed,
+// so that the search documents of their articles carry the new name.
+const EventAuthorUpdated = "author.updated"
+
+// StoredName is an author's name as stored, with the key it is matched on.
+type StoredName struct {
+	AuthorID string
+	Name     string
+	NameKey  string
+}
+
 type Author struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	Slug          string        `json:"slug"`
+	Bio           string        `json:"bio"`
+	AvatarURL     string        `json:"avatar_url"`
+	SocialHandles SocialHandles `json:"social_handles"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// SocialHandles are an author's accounts on social networks, stored as JSON.
+type SocialHandles struct {
+	Twitter   string `json:"twitter,omitempty"`
+	Instagram string `json:"instagram,omitempty"`
+	Facebook  string `json:"facebook,omitempty"`
+	LinkedIn  string `json:"linkedin,omitempty"`
+}
+
+// Value stores the handles in a JSONB column.
+func (h SocialHandles) Value() (driver.Value, error) {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan reads the handles from a JSONB column.
+func (h *SocialHandles) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*h = SocialHandles{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, h)
+	case string:
+		return json.Unmarshal([]byte(v), h)
+	default:
+		return fmt.Errorf("cannot scan %T into SocialHandles", src)
+	}
+}
+
+// CreateAuthorRequest represents the request body for creating an author.
+// The slug is derived from the name when empty.
+type CreateAuthorRequest struct {
+	Name          string        `json:"name"`
+	Slug          string        `json:"slug"`
+	Bio           string        `json:"bio"`
+	AvatarURL     string        `json:"avatar_url"`
+	SocialHandles SocialHandles `json:"social_handles"`
+}
+
+// UpdateAuthorRequest represents the request body for replacing an author's profile.
+// The current slug is kept when empty.
+type UpdateAuthorRequest struct {
+	Name          string        `json:"name"`
+	Slug          string        `json:"slug"`
+	Bio           string        `json:"bio"`
+	AvatarURL     string        `json:"avatar_url"`
+	SocialHandles SocialHandles `json:"social_handles"`
+}
+
+// AuthorList is a page of authors, ordered by name.
+type AuthorList struct {
+	Data  []*Author `json:"data"`
+	Total int64     `json:"total"`
+	Page  int       `json:"page"`
+	Limit int       `json:"limit"`
+}
+
+// MergeAuthorsRequest represents the request body for merging duplicate authors into a canonical one.
+type MergeAuthorsRequest struct {
+	DuplicateIDs []string `json:"duplicate_ids"`
+}
+
+// MergeResult reports the canonical author of a merge and what was merged into them.
+type MergeResult struct {
+	Author          *Author  `json:"author"`
+	MergedAuthorIDs []string `json:"merged_author_ids"`
+	ArticlesMoved   int64    `json:"articles_moved"`
 }
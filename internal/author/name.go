@@ -0,0 +1,58 @@
+package author
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	nameFolder  = cases.Fold()
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// NormalizeName tidies an author name for display: Unicode NFC composition, surrounding whitespace
+// trimmed and inner runs of whitespace collapsed to a single space.
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+}
+
+// NameKey returns the key that author names are matched on, so that "Budi Santoso", "budi santoso "
+// and "Budi  Santoso" are the same author. It is the normalized name, case folded. It is the only place
+// keys are computed: SQL does not fold case the same way, so RepairNameKeys rewrites keys stored by migrations.
+func NameKey(name string) string {
+	return nameFolder.String(NormalizeName(name))
+}
+
+// Slugify derives the URL slug of an author name: lower case ASCII letters and digits separated by single hyphens,
+// with accents dropped, so "André  Santoso" becomes "andre-santoso". Names without any such character get "author".
+func Slugify(name string) string {
+	var b strings.Builder
+	separate := false
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Accents are decomposed into combining marks and dropped
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if separate && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			separate = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			separate = true
+		}
+	}
+	if b.Len() == 0 {
+		return "author"
+	}
+	return b.String()
+}
+
+// IsValidSlug reports whether s has the form of the slugs made by Slugify.
+func IsValidSlug(s string) bool {
+	return slugPattern.MatchString(s)
+}
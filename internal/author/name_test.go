@@ -0,0 +1,39 @@
+package author_test
+
+import (
+	"kumparan-test/internal/author"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "Budi Santoso", author.NormalizeName("  Budi \t Santoso\n"))
+	// A decomposed "é" (e + combining acute accent) is composed
+	assert.Equal(t, "Andr\u00e9", author.NormalizeName("Andre\u0301"))
+}
+
+func TestNameKey_MatchesVariantsOfTheSameName(t *testing.T) {
+	for _, name := range []string{"Budi Santoso", "budi santoso ", "Budi  Santoso", "BUDI SANTOSO"} {
+		assert.Equal(t, "budi santoso", author.NameKey(name), name)
+	}
+	assert.Equal(t, author.NameKey("Andr\u00e9"), author.NameKey("ANDRE\u0301"))
+	assert.NotEqual(t, author.NameKey("Budi Santoso"), author.NameKey("Budi Santosa"))
+	assert.Equal(t, author.NameKey("Straße"), author.NameKey("STRASSE"))
+	assert.Equal(t, author.NameKey("Budi Santoso"), author.NameKey("Budi\u00a0Santoso"))
+}
+
+func TestSlugify(t *testing.T) {
+	for name, slug := range map[string]string{
+		"Budi Santoso":       "budi-santoso",
+		" Budi  Santoso ":    "budi-santoso",
+		"Andre\u0301 O'Neil": "andre-o-neil",
+		"Tim Redaksi #2":     "tim-redaksi-2",
+		"\u738b\u5c0f\u660e": "author",
+	} {
+		assert.Equal(t, slug, author.Slugify(name), name)
+		assert.True(t, author.IsValidSlug(author.Slugify(name)), name)
+	}
+	assert.False(t, author.IsValidSlug("Budi Santoso"))
+	assert.False(t, author.IsValidSlug("budi--santoso"))
+}
@@ -3,11 +3,40 @@ package author
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+
+	"kumparan-test/internal/outbox"
+
+	"github.com/jackc/pgconn"
+	"github.com/lib/pq"
+)
+
+const (
+	// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+	uniqueViolation = "23505"
+
+	nameKeyConstraint = "authors_name_key_unique"
+	slugConstraint    = "authors_slug_key"
+
+	// maxSlugAttempts bounds the numbered variants of a derived slug tried when it is taken
+	maxSlugAttempts = 20
+
+	// columns are the author columns every query returns, in the order of scanFields
+	columns = "id, name, slug, bio, avatar_url, social_handles, created_at, updated_at"
 )
 
 type Repository interface {
 	CreateAuthor(ctx context.Context, author *Author) (*Author, error)
 	GetAuthorByName(ctx context.Context, name string) (*Author, error)
+	UpsertAuthor(ctx context.Context, name string) (*Author, error)
+	GetAuthors(ctx context.Context, limit, offset int) ([]*Author, int64, error)
+	GetAuthorByID(ctx context.Context, id string) (*Author, error)
+	GetAuthorBySlug(ctx context.Context, slug string) (*Author, error)
+	UpdateAuthor(ctx context.Context, author *Author) (*Author, error)
+	DeleteAuthor(ctx context.Context, id string) error
+	MergeAuthors(ctx context.Context, canonicalID string, duplicateIDs []string) (int64, error)
+	GetStoredNames(ctx context.Context) ([]*StoredName, error)
 }
 
 type postgresRepository struct {
@@ -18,23 +47,305 @@ func NewPostgresRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-// CreateAuthor inserts a new author into the database.
+// scanFields returns the scan destinations of the author columns.
+func scanFields(author *Author) []interface{} {
+	return []interface{}{
+		&author.ID, &author.Name, &author.Slug, &author.Bio, &author.AvatarURL,
+		&author.SocialHandles, &author.CreatedAt, &author.UpdatedAt,
+	}
+}
+
+// CreateAuthor inserts a new author into the database, deriving their slug from the name when it is empty.
+// It returns ErrAuthorExists when another author already has the name, compared by NameKey, and
+// ErrSlugExists when the given slug is taken.
 func (r *postgresRepository) CreateAuthor(ctx context.Context, author *Author) (*Author, error) {
-	query := `INSERT INTO authors (name) VALUES ($1) RETURNING id`
-	err := r.db.QueryRow(query, author.Name).Scan(&author.ID)
+	query := `INSERT INTO authors (name, name_key, slug, bio, avatar_url, social_handles) VALUES ($1, $2, $3, $4, $5, $6) RETURNING ` + columns
+	insert := func(slug string) error {
+		return r.db.QueryRowContext(ctx, query, author.Name, NameKey(author.Name), slug, author.Bio, author.AvatarURL, author.SocialHandles).
+			Scan(scanFields(author)...)
+	}
+
+	var err error
+	if author.Slug != "" {
+		err = insert(author.Slug)
+	} else {
+		err = withFreeSlug(author.Name, insert)
+	}
 	if err != nil {
-		return nil, err
+		return nil, uniqueError(err)
 	}
 	return author, nil
 }
 
-// GetAuthorByName retrieves an author by their name.
+// GetAuthorByName retrieves an author by their name, ignoring differences in case, spacing and Unicode form.
 func (r *postgresRepository) GetAuthorByName(ctx context.Context, name string) (*Author, error) {
-	query := `SELECT id, name FROM authors WHERE name = $1`
+	query := `SELECT ` + columns + ` FROM authors WHERE name_key = $1`
+	var author Author
+	err := r.db.QueryRowContext(ctx, query, NameKey(name)).Scan(scanFields(&author)...)
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+// UpsertAuthor returns the author with the given name, compared by NameKey, inserting them first if
+// they do not exist yet. It is a single statement, so concurrent calls for a new name all get the same
+// author. The no-op update on conflict is what makes RETURNING yield the existing row, whose name is kept.
+func (r *postgresRepository) UpsertAuthor(ctx context.Context, name string) (*Author, error) {
+	query := `INSERT INTO authors (name, name_key, slug) VALUES ($1, $2, $3)
+		ON CONFLICT (name_key) DO UPDATE SET name_key = EXCLUDED.name_key
+		RETURNING ` + columns
+	var author Author
+	err := withFreeSlug(name, func(slug string) error {
+		return r.db.QueryRowContext(ctx, query, name, NameKey(name), slug).Scan(scanFields(&author)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+// GetAuthors retrieves a page of authors ordered by name, together with the total number of authors.
+func (r *postgresRepository) GetAuthors(ctx context.Context, limit, offset int) ([]*Author, int64, error) {
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM authors`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + columns + ` FROM authors ORDER BY name, id LIMIT $1 OFFSET $2`
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	authors := []*Author{}
+	for rows.Next() {
+		var author Author
+		if err := rows.Scan(scanFields(&author)...); err != nil {
+			return nil, 0, err
+		}
+		authors = append(authors, &author)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+
+	return authors, total, nil
+}
+
+// GetAuthorByID retrieves a single author by their ID.
+// It returns sql.ErrNoRows when the author does not exist.
+func (r *postgresRepository) GetAuthorByID(ctx context.Context, id string) (*Author, error) {
+	query := `SELECT ` + columns + ` FROM authors WHERE id = $1`
+	var author Author
+	err := r.db.QueryRowContext(ctx, query, id).Scan(scanFields(&author)...)
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+// GetAuthorBySlug retrieves a single author by their slug.
+// It returns sql.ErrNoRows when no author has the slug.
+func (r *postgresRepository) GetAuthorBySlug(ctx context.Context, slug string) (*Author, error) {
+	query := `SELECT ` + columns + ` FROM authors WHERE slug = $1`
 	var author Author
-	err := r.db.QueryRow(query, name).Scan(&author.ID, &author.Name)
+	err := r.db.QueryRowContext(ctx, query, slug).Scan(scanFields(&author)...)
 	if err != nil {
 		return nil, err
 	}
 	return &author, nil
 }
+
+// UpdateAuthor replaces an author's profile, keeping their slug when it is empty. A rename is saved together
+// with the outbox event re-indexing their articles. It returns sql.ErrNoRows when the author does not exist,
+// ErrAuthorExists when another author has the name and ErrSlugExists when another author has the slug.
+func (r *postgresRepository) UpdateAuthor(ctx context.Context, author *Author) (*Author, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var oldName string
+	if err := tx.QueryRowContext(ctx, `SELECT name FROM authors WHERE id = $1 FOR UPDATE`, author.ID).Scan(&oldName); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE authors SET name = $1, name_key = $2, slug = COALESCE(NULLIF($3, ''), slug), bio = $4, avatar_url = $5,
+		social_handles = $6, updated_at = CURRENT_TIMESTAMP WHERE id = $7 RETURNING ` + columns
+	err = tx.QueryRowContext(ctx, query, author.Name, NameKey(author.Name), author.Slug, author.Bio, author.AvatarURL, author.SocialHandles, author.ID).
+		Scan(scanFields(author)...)
+	if err != nil {
+		return nil, uniqueError(err)
+	}
+
+	if author.Name != oldName {
+		if err := outbox.Enqueue(ctx, tx, &outbox.Event{AggregateID: author.ID, EventType: EventAuthorUpdated}); err != nil {
+			return nil, fmt.Errorf("failed to enqueue outbox event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+// DeleteAuthor removes an author who is not credited on any article.
+// It returns sql.ErrNoRows when the author does not exist and ErrAuthorHasArticles when they still have articles.
+func (r *postgresRepository) DeleteAuthor(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locking the author makes articles being posted for them wait until the delete is decided
+	var lockedID string
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM authors WHERE id = $1 FOR UPDATE`, id).Scan(&lockedID); err != nil {
+		return err
+	}
+
+	var hasArticles bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM article_authors WHERE author_id = $1)`, id).Scan(&hasArticles); err != nil {
+		return err
+	}
+	if hasArticles {
+		return ErrAuthorHasArticles
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM authors WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MergeAuthors credits the canonical author in place of the duplicate authors and deletes the duplicates,
+// together with the outbox event re-indexing the canonical author's articles. An article crediting several of
+// them keeps only its first such byline. It returns how many articles were moved, or sql.ErrNoRows when the
+// canonical author or any of the duplicates does not exist.
+func (r *postgresRepository) MergeAuthors(ctx context.Context, canonicalID string, duplicateIDs []string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Locked in a stable order so that concurrent merges of overlapping authors cannot deadlock
+	ids := append([]string{canonicalID}, duplicateIDs...)
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM authors WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return 0, rows.Err()
+	}
+	if locked != len(ids) {
+		return 0, sql.ErrNoRows
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE articles SET author_id = CASE WHEN author_id = ANY($2) THEN $1 ELSE author_id END,
+		updated_at = CURRENT_TIMESTAMP WHERE id IN (SELECT article_id FROM article_authors WHERE author_id = ANY($2))`,
+		canonicalID, pq.Array(duplicateIDs))
+	if err != nil {
+		return 0, err
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	// Once an article credits one of the merged authors at most once, their bylines can become the canonical author's
+	if _, err := tx.ExecContext(ctx, `DELETE FROM article_authors later USING article_authors earlier
+		WHERE later.author_id = ANY($1) AND earlier.author_id = ANY($1)
+		AND earlier.article_id = later.article_id AND earlier.position < later.position`, pq.Array(ids)); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE article_authors SET author_id = $1 WHERE author_id = ANY($2)`,
+		canonicalID, pq.Array(duplicateIDs)); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM authors WHERE id = ANY($1)`, pq.Array(duplicateIDs)); err != nil {
+		return 0, err
+	}
+
+	if err := outbox.Enqueue(ctx, tx, &outbox.Event{AggregateID: canonicalID, EventType: EventAuthorUpdated}); err != nil {
+		return 0, fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// GetStoredNames returns the name and name key of every author, earliest created first.
+func (r *postgresRepository) GetStoredNames(ctx context.Context) ([]*StoredName, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, name_key FROM authors ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []*StoredName{}
+	for rows.Next() {
+		var name StoredName
+		if err := rows.Scan(&name.AuthorID, &name.Name, &name.NameKey); err != nil {
+			return nil, err
+		}
+		names = append(names, &name)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return names, nil
+}
+
+// violatedConstraint returns the unique constraint that err reports as violated, or "" for any other error.
+func violatedConstraint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
+// uniqueError translates a violation of the name or slug constraint into ErrAuthorExists or ErrSlugExists.
+func uniqueError(err error) error {
+	switch violatedConstraint(err) {
+	case nameKeyConstraint:
+		return ErrAuthorExists
+	case slugConstraint:
+		return ErrSlugExists
+	default:
+		return err
+	}
+}
+
+// withFreeSlug calls insert with the slug derived from name and, while that slug is taken by another author,
+// with its numbered variants: budi-santoso, budi-santoso-2, budi-santoso-3 and so on.
+func withFreeSlug(name string, insert func(slug string) error) error {
+	base := Slugify(name)
+	slug := base
+	for attempt := 1; ; attempt++ {
+		err := insert(slug)
+		if violatedConstraint(err) != slugConstraint || attempt == maxSlugAttempts {
+			return err
+		}
+		slug = fmt.Sprintf("%s-%d", base, attempt+1)
+	}
+}
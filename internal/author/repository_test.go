@@ -6,12 +6,19 @@ import (
 	"errors"
 	"kumparan-test/internal/author"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/lib/pq"
 
 	"github.com/stretchr/testify/assert"
 )
 
+const authorColumns = `id, name, slug, bio, avatar_url, social_handles, created_at, updated_at`
+
+var createdAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
 func setupRepoWithMock(t *testing.T) (author.Repository, sqlmock.Sqlmock, func()) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -21,19 +28,33 @@ func setupRepoWithMock(t *testing.T) (author.Repository, sqlmock.Sqlmock, func()
 	return author.NewPostgresRepository(db), mock, func() { db.Close() }
 }
 
+// authorRows returns the result rows of the given authors, as the author columns are selected.
+func authorRows(authors ...*author.Author) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "name", "slug", "bio", "avatar_url", "social_handles", "created_at", "updated_at"})
+	for _, a := range authors {
+		handles, _ := a.SocialHandles.Value()
+		rows.AddRow(a.ID, a.Name, a.Slug, a.Bio, a.AvatarURL, []byte(handles.(string)), a.CreatedAt, a.UpdatedAt)
+	}
+	return rows
+}
+
 func TestCreateAuthor_Success(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	mock.ExpectQuery(`INSERT INTO authors \(name\) VALUES \(\$1\) RETURNING id`).
-		WithArgs("Bara Biri").
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("auth-1"))
+	stored := &author.Author{
+		ID: "auth-1", Name: "Bara Biri", Slug: "bara-biri", Bio: "Jurnalis",
+		SocialHandles: author.SocialHandles{Twitter: "barabiri"}, CreatedAt: createdAt, UpdatedAt: createdAt,
+	}
+	mock.ExpectQuery(`INSERT INTO authors \(name, name_key, slug, bio, avatar_url, social_handles\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\) RETURNING `+authorColumns).
+		WithArgs("Bara Biri", "bara biri", "bara-biri", "Jurnalis", "", author.SocialHandles{Twitter: "barabiri"}).
+		WillReturnRows(authorRows(stored))
 
-	a := &author.Author{Name: "Bara Biri"}
+	a := &author.Author{Name: "Bara Biri", Bio: "Jurnalis", SocialHandles: author.SocialHandles{Twitter: "barabiri"}}
 	result, err := repo.CreateAuthor(context.Background(), a)
 
 	assert.NoError(t, err)
-	assert.Equal(t, "auth-1", result.ID)
+	assert.Equal(t, stored, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -41,8 +62,8 @@ func TestCreateAuthor_Error(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	mock.ExpectQuery(`INSERT INTO authors \(name\) VALUES \(\$1\) RETURNING id`).
-		WithArgs("Bara Biri").
+	mock.ExpectQuery(`INSERT INTO authors`).
+		WithArgs("Bara Biri", "bara biri", "bara-biri", "", "", author.SocialHandles{}).
 		WillReturnError(errors.New("insert failed"))
 
 	a := &author.Author{Name: "Bara Biri"}
@@ -53,13 +74,65 @@ func TestCreateAuthor_Error(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateAuthor_DuplicateName(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`INSERT INTO authors`).
+		WithArgs("Bara Biri", "bara biri", "bara-biri", "", "", author.SocialHandles{}).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "authors_name_key_unique"})
+
+	result, err := repo.CreateAuthor(context.Background(), &author.Author{Name: "Bara Biri"})
+
+	assert.ErrorIs(t, err, author.ErrAuthorExists)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAuthor_TakenSlugIsNumbered(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	slugTaken := &pgconn.PgError{Code: "23505", ConstraintName: "authors_slug_key"}
+	mock.ExpectQuery(`INSERT INTO authors`).
+		WithArgs("Bara-Biri", "bara-biri", "bara-biri", "", "", author.SocialHandles{}).
+		WillReturnError(slugTaken)
+	mock.ExpectQuery(`INSERT INTO authors`).
+		WithArgs("Bara-Biri", "bara-biri", "bara-biri-2", "", "", author.SocialHandles{}).
+		WillReturnError(slugTaken)
+	mock.ExpectQuery(`INSERT INTO authors`).
+		WithArgs("Bara-Biri", "bara-biri", "bara-biri-3", "", "", author.SocialHandles{}).
+		WillReturnRows(authorRows(&author.Author{ID: "auth-3", Name: "Bara-Biri", Slug: "bara-biri-3"}))
+
+	result, err := repo.CreateAuthor(context.Background(), &author.Author{Name: "Bara-Biri"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bara-biri-3", result.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAuthor_RequestedSlugTaken(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`INSERT INTO authors`).
+		WithArgs("Bara Biri", "bara biri", "bara", "", "", author.SocialHandles{}).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "authors_slug_key"})
+
+	result, err := repo.CreateAuthor(context.Background(), &author.Author{Name: "Bara Biri", Slug: "bara"})
+
+	assert.ErrorIs(t, err, author.ErrSlugExists)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetAuthorByName_Success(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	mock.ExpectQuery(`SELECT id, name FROM authors WHERE name = \$1`).
-		WithArgs("Bara Biri").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("auth-1", "Bara Biri"))
+	mock.ExpectQuery(`SELECT ` + authorColumns + ` FROM authors WHERE name_key = \$1`).
+		WithArgs("bara biri").
+		WillReturnRows(authorRows(&author.Author{ID: "auth-1", Name: "Bara Biri", Slug: "bara-biri"}))
 
 	result, err := repo.GetAuthorByName(context.Background(), "Bara Biri")
 
@@ -73,8 +146,8 @@ func TestGetAuthorByName_NotFound(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	mock.ExpectQuery(`SELECT id, name FROM authors WHERE name = \$1`).
-		WithArgs("Unknown").
+	mock.ExpectQuery(`SELECT ` + authorColumns + ` FROM authors WHERE name_key = \$1`).
+		WithArgs("unknown").
 		WillReturnError(sql.ErrNoRows)
 
 	result, err := repo.GetAuthorByName(context.Background(), "Unknown")
@@ -88,8 +161,8 @@ func TestGetAuthorByName_ScanError(t *testing.T) {
 	repo, mock, cleanup := setupRepoWithMock(t)
 	defer cleanup()
 
-	mock.ExpectQuery(`SELECT id, name FROM authors WHERE name = \$1`).
-		WithArgs("Bara Biri").
+	mock.ExpectQuery(`SELECT ` + authorColumns + ` FROM authors WHERE name_key = \$1`).
+		WithArgs("bara biri").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("auth-1"))
 
 	result, err := repo.GetAuthorByName(context.Background(), "Bara Biri")
@@ -98,3 +171,296 @@ func TestGetAuthorByName_ScanError(t *testing.T) {
 	assert.Nil(t, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// upsertAuthorQuery is the single statement that makes concurrent first posts by a new author share one row:
+// the unique index on name_key turns the losing inserts into a no-op update, and RETURNING yields the winner.
+const upsertAuthorQuery = `INSERT INTO authors \(name, name_key, slug\) VALUES \(\$1, \$2, \$3\)\s+` +
+	`ON CONFLICT \(name_key\) DO UPDATE SET name_key = EXCLUDED.name_key\s+RETURNING ` + authorColumns
+
+func TestUpsertAuthor_InsertsNewAuthor(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	stored := &author.Author{ID: "auth-1", Name: "Bara Biri", Slug: "bara-biri", CreatedAt: createdAt, UpdatedAt: createdAt}
+	mock.ExpectQuery(upsertAuthorQuery).
+		WithArgs("Bara Biri", "bara biri", "bara-biri").
+		WillReturnRows(authorRows(stored))
+
+	result, err := repo.UpsertAuthor(context.Background(), "Bara Biri")
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertAuthor_ReturnsExistingAuthorOnNameConflict(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	// Another request inserted the author first: RETURNING yields their row, keeping its name and slug
+	existing := &author.Author{ID: "auth-1", Name: "Bara Biri", Slug: "bara-biri", CreatedAt: createdAt, UpdatedAt: createdAt}
+	mock.ExpectQuery(upsertAuthorQuery).
+		WithArgs("BARA BIRI", "bara biri", "bara-biri").
+		WillReturnRows(authorRows(existing))
+
+	result, err := repo.UpsertAuthor(context.Background(), "BARA BIRI")
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertAuthor_TakenSlugIsNumbered(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(upsertAuthorQuery).
+		WithArgs("Bara-Biri", "bara-biri", "bara-biri").
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "authors_slug_key"})
+	mock.ExpectQuery(upsertAuthorQuery).
+		WithArgs("Bara-Biri", "bara-biri", "bara-biri-2").
+		WillReturnRows(authorRows(&author.Author{ID: "auth-2", Name: "Bara-Biri", Slug: "bara-biri-2"}))
+
+	result, err := repo.UpsertAuthor(context.Background(), "Bara-Biri")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bara-biri-2", result.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertAuthor_Error(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(upsertAuthorQuery).
+		WithArgs("Bara Biri", "bara biri", "bara-biri").
+		WillReturnError(errors.New("insert failed"))
+
+	result, err := repo.UpsertAuthor(context.Background(), "Bara Biri")
+
+	assert.ErrorContains(t, err, "insert failed")
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuthors_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	sekar := &author.Author{ID: "auth-3", Name: "Sekar", Slug: "sekar", CreatedAt: createdAt, UpdatedAt: createdAt}
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM authors`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT `+authorColumns+` FROM authors ORDER BY name, id LIMIT \$1 OFFSET \$2`).
+		WithArgs(2, 2).
+		WillReturnRows(authorRows(sekar))
+
+	authors, total, err := repo.GetAuthors(context.Background(), 2, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []*author.Author{sekar}, authors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuthorBySlug(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT ` + authorColumns + ` FROM authors WHERE slug = \$1`).
+		WithArgs("sekar").
+		WillReturnRows(authorRows(&author.Author{ID: "auth-3", Name: "Sekar", Slug: "sekar"}))
+
+	result, err := repo.GetAuthorBySlug(context.Background(), "sekar")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "auth-3", result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAuthor_RenameEnqueuesReindex(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT name FROM authors WHERE id = \$1 FOR UPDATE`).
+		WithArgs("auth-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Bara"))
+	mock.ExpectQuery(`UPDATE authors SET name = \$1, name_key = \$2, slug = COALESCE\(NULLIF\(\$3, ''\), slug\), bio = \$4`).
+		WithArgs("Bara Putra", "bara putra", "", "Redaktur", "", author.SocialHandles{}, "auth-1").
+		WillReturnRows(authorRows(&author.Author{ID: "auth-1", Name: "Bara Putra", Slug: "bara", Bio: "Redaktur"}))
+	mock.ExpectExec(`INSERT INTO outbox_events \(aggregate_id, event_type\)`).
+		WithArgs("auth-1", author.EventAuthorUpdated).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := repo.UpdateAuthor(context.Background(), &author.Author{ID: "auth-1", Name: "Bara Putra", Bio: "Redaktur"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bara Putra", result.Name)
+	assert.Equal(t, "bara", result.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAuthor_ProfileOnlyDoesNotReindex(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT name FROM authors WHERE id = \$1 FOR UPDATE`).
+		WithArgs("auth-1").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Bara"))
+	mock.ExpectQuery(`UPDATE authors SET`).
+		WithArgs("Bara", "bara", "bara-b", "Redaktur", "", author.SocialHandles{}, "auth-1").
+		WillReturnRows(authorRows(&author.Author{ID: "auth-1", Name: "Bara", Slug: "bara-b", Bio: "Redaktur"}))
+	mock.ExpectCommit()
+
+	result, err := repo.UpdateAuthor(context.Background(), &author.Author{ID: "auth-1", Name: "Bara", Slug: "bara-b", Bio: "Redaktur"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bara-b", result.Slug)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAuthor_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT name FROM authors WHERE id = \$1 FOR UPDATE`).
+		WithArgs("auth-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	result, err := repo.UpdateAuthor(context.Background(), &author.Author{ID: "auth-1", Name: "Bara Putra"})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAuthor_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM authors WHERE id = \$1 FOR UPDATE`).
+		WithArgs("auth-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("auth-1"))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM article_authors WHERE author_id = \$1\)`).
+		WithArgs("auth-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`DELETE FROM authors WHERE id = \$1`).
+		WithArgs("auth-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.DeleteAuthor(context.Background(), "auth-1")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAuthor_HasArticles(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM authors WHERE id = \$1 FOR UPDATE`).
+		WithArgs("auth-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("auth-1"))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM article_authors WHERE author_id = \$1\)`).
+		WithArgs("auth-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err := repo.DeleteAuthor(context.Background(), "auth-1")
+
+	assert.ErrorIs(t, err, author.ErrAuthorHasArticles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAuthor_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM authors WHERE id = \$1 FOR UPDATE`).
+		WithArgs("auth-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err := repo.DeleteAuthor(context.Background(), "auth-1")
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMergeAuthors_MovesArticlesAndDeletesDuplicates(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM authors WHERE id = ANY\(\$1\) ORDER BY id FOR UPDATE`).
+		WithArgs(pq.Array([]string{"auth-1", "auth-2", "auth-3"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("auth-1").AddRow("auth-2").AddRow("auth-3"))
+	mock.ExpectExec(`UPDATE articles SET author_id = CASE WHEN author_id = ANY\(\$2\) THEN \$1 ELSE author_id END,\s+`+
+		`updated_at = CURRENT_TIMESTAMP WHERE id IN \(SELECT article_id FROM article_authors WHERE author_id = ANY\(\$2\)\)`).
+		WithArgs("auth-1", pq.Array([]string{"auth-2", "auth-3"})).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	// Bylines of an article that credited several of the merged authors are dropped but the first
+	mock.ExpectExec(`DELETE FROM article_authors later USING article_authors earlier`).
+		WithArgs(pq.Array([]string{"auth-1", "auth-2", "auth-3"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE article_authors SET author_id = \$1 WHERE author_id = ANY\(\$2\)`).
+		WithArgs("auth-1", pq.Array([]string{"auth-2", "auth-3"})).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM authors WHERE id = ANY\(\$1\)`).
+		WithArgs(pq.Array([]string{"auth-2", "auth-3"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO outbox_events \(aggregate_id, event_type\)`).
+		WithArgs("auth-1", author.EventAuthorUpdated).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	moved, err := repo.MergeAuthors(context.Background(), "auth-1", []string{"auth-2", "auth-3"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), moved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMergeAuthors_MissingAuthor(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM authors WHERE id = ANY\(\$1\) ORDER BY id FOR UPDATE`).
+		WithArgs(pq.Array([]string{"auth-1", "auth-2"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("auth-1"))
+	mock.ExpectRollback()
+
+	moved, err := repo.MergeAuthors(context.Background(), "auth-1", []string{"auth-2"})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Zero(t, moved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStoredNames(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT id, name, name_key FROM authors ORDER BY created_at, id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "name_key"}).
+			AddRow("auth-1", "Straße", "straße").
+			AddRow("auth-2", "Bara", "bara"))
+
+	names, err := repo.GetStoredNames(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*author.StoredName{
+		{AuthorID: "auth-1", Name: "Straße", NameKey: "straße"},
+		{AuthorID: "auth-2", Name: "Bara", NameKey: "bara"},
+	}, names)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
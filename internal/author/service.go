@@ -4,16 +4,29 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/sirupsen/logrus"
 )
 
 var (
-	ErrInternalDBError = errors.New("internal database error")
+	ErrInternalDBError   = errors.New("internal database error")
+	ErrAuthorNotFound    = errors.New("author not found")
+	ErrAuthorExists      = errors.New("author already exists")
+	ErrAuthorHasArticles = errors.New("author has articles")
+	ErrInvalidMerge      = errors.New("invalid author merge")
+	ErrSlugExists        = errors.New("author slug already taken")
 )
 
 type Service interface {
 	GetOrCreateAuthor(ctx context.Context, name string) (*Author, error)
+	GetAuthors(ctx context.Context, page, limit int) (*AuthorList, error)
+	GetAuthorByID(ctx context.Context, id string) (*Author, error)
+	GetAuthorBySlug(ctx context.Context, slug string) (*Author, error)
+	CreateAuthor(ctx context.Context, req *CreateAuthorRequest) (*Author, error)
+	UpdateAuthor(ctx context.Context, id string, req *UpdateAuthorRequest) (*Author, error)
+	DeleteAuthor(ctx context.Context, id string) error
+	MergeAuthors(ctx context.Context, canonicalID string, req *MergeAuthorsRequest) (*MergeResult, error)
 }
 
 type authorService struct {
@@ -25,20 +38,19 @@ func NewAuthorService(repo Repository) Service {
 }
 
 // GetOrCreateAuthor attempts to get an author by name; if not found, it creates them.
+// Names are matched by NameKey, and creation is an upsert, so concurrent first posts by a new author share a single author.
 func (s *authorService) GetOrCreateAuthor(ctx context.Context, name string) (*Author, error) {
+	name = NormalizeName(name)
 	author, err := s.repo.GetAuthorByName(ctx, name)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			// Author not found, create new
-			newAuthor := &Author{
-				Name: name,
-			}
-			createdAuthor, createErr := s.repo.CreateAuthor(ctx, newAuthor)
+			// Author not found, create new unless a concurrent request just did
+			createdAuthor, createErr := s.repo.UpsertAuthor(ctx, name)
 			if createErr != nil {
 				logrus.WithError(createErr).Error("Failed to create new author in DB")
 				return nil, ErrInternalDBError
 			}
-			logrus.WithField("author_id", createdAuthor.ID).Info("New author created successfully")
+			logrus.WithField("author_id", createdAuthor.ID).Info("Author upserted")
 			return createdAuthor, nil
 		}
 
@@ -48,3 +60,225 @@ func (s *authorService) GetOrCreateAuthor(ctx context.Context, name string) (*Au
 
 	return author, nil
 }
+
+// GetAuthors returns a page of authors ordered by name. Page defaults to 1 and limit to 10, at most 100.
+func (s *authorService) GetAuthors(ctx context.Context, page, limit int) (*AuthorList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	authors, total, err := s.repo.GetAuthors(ctx, limit, (page-1)*limit)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list authors from DB")
+		return nil, ErrInternalDBError
+	}
+
+	return &AuthorList{Data: authors, Total: total, Page: page, Limit: limit}, nil
+}
+
+// GetAuthorByID retrieves a single author, returning ErrAuthorNotFound if it does not exist.
+func (s *authorService) GetAuthorByID(ctx context.Context, id string) (*Author, error) {
+	author, err := s.repo.GetAuthorByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAuthorNotFound
+		}
+		logrus.WithError(err).WithField("author_id", id).Error("Failed to get author from DB")
+		return nil, ErrInternalDBError
+	}
+
+	return author, nil
+}
+
+// GetAuthorBySlug retrieves a single author by their slug, returning ErrAuthorNotFound if no author has it.
+func (s *authorService) GetAuthorBySlug(ctx context.Context, slug string) (*Author, error) {
+	author, err := s.repo.GetAuthorBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAuthorNotFound
+		}
+		logrus.WithError(err).WithField("slug", slug).Error("Failed to get author by slug from DB")
+		return nil, ErrInternalDBError
+	}
+
+	return author, nil
+}
+
+// CreateAuthor creates an author, returning ErrAuthorExists if one already has the name
+// and ErrSlugExists if the requested slug is taken.
+func (s *authorService) CreateAuthor(ctx context.Context, req *CreateAuthorRequest) (*Author, error) {
+	author, err := s.repo.CreateAuthor(ctx, &Author{
+		Name:          NormalizeName(req.Name),
+		Slug:          req.Slug,
+		Bio:           req.Bio,
+		AvatarURL:     req.AvatarURL,
+		SocialHandles: req.SocialHandles,
+	})
+	if err != nil {
+		if errors.Is(err, ErrAuthorExists) || errors.Is(err, ErrSlugExists) {
+			return nil, err
+		}
+		logrus.WithError(err).Error("Failed to create new author in DB")
+		return nil, ErrInternalDBError
+	}
+
+	logrus.WithField("author_id", author.ID).Info("New author created successfully")
+	return author, nil
+}
+
+// UpdateAuthor replaces an author's profile. When renamed, their articles are re-indexed under the new name
+// in the background, through the outbox. It returns ErrAuthorNotFound if the author does not exist, and
+// ErrAuthorExists or ErrSlugExists if another author already has the name or slug.
+func (s *authorService) UpdateAuthor(ctx context.Context, id string, req *UpdateAuthorRequest) (*Author, error) {
+	author, err := s.repo.UpdateAuthor(ctx, &Author{
+		ID:            id,
+		Name:          NormalizeName(req.Name),
+		Slug:          req.Slug,
+		Bio:           req.Bio,
+		AvatarURL:     req.AvatarURL,
+		SocialHandles: req.SocialHandles,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAuthorNotFound
+		}
+		if errors.Is(err, ErrAuthorExists) || errors.Is(err, ErrSlugExists) {
+			return nil, err
+		}
+		logrus.WithError(err).WithField("author_id", id).Error("Failed to update author in DB")
+		return nil, ErrInternalDBError
+	}
+
+	return author, nil
+}
+
+// DeleteAuthor removes an author. It returns ErrAuthorNotFound if the author does not exist and
+// ErrAuthorHasArticles if they still have articles, which must be deleted or reassigned first.
+func (s *authorService) DeleteAuthor(ctx context.Context, id string) error {
+	err := s.repo.DeleteAuthor(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAuthorNotFound
+		}
+		if errors.Is(err, ErrAuthorHasArticles) {
+			return ErrAuthorHasArticles
+		}
+		logrus.WithError(err).WithField("author_id", id).Error("Failed to delete author in DB")
+		return ErrInternalDBError
+	}
+
+	return nil
+}
+
+// MergeAuthors merges duplicate authors into the canonical one: their articles are moved to the canonical author,
+// who is then the only one left, and re-indexed in the background through the outbox. It returns ErrInvalidMerge
+// when there is nothing to merge or the canonical author is among the duplicates, and ErrAuthorNotFound when any
+// of the authors does not exist.
+func (s *authorService) MergeAuthors(ctx context.Context, canonicalID string, req *MergeAuthorsRequest) (*MergeResult, error) {
+	seen := map[string]bool{}
+	var duplicateIDs []string
+	for _, id := range req.DuplicateIDs {
+		if id == canonicalID {
+			return nil, fmt.Errorf("%w: an author cannot be merged into themselves", ErrInvalidMerge)
+		}
+		if !seen[id] {
+			seen[id] = true
+			duplicateIDs = append(duplicateIDs, id)
+		}
+	}
+	if len(duplicateIDs) == 0 {
+		return nil, fmt.Errorf("%w: no duplicate authors given", ErrInvalidMerge)
+	}
+
+	moved, err := s.repo.MergeAuthors(ctx, canonicalID, duplicateIDs)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAuthorNotFound
+		}
+		logrus.WithError(err).WithField("author_id", canonicalID).Error("Failed to merge authors in DB")
+		return nil, ErrInternalDBError
+	}
+
+	canonical, err := s.GetAuthorByID(ctx, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"author_id":      canonicalID,
+		"merged_authors": duplicateIDs,
+		"articles_moved": moved,
+	}).Info("Duplicate authors merged")
+	return &MergeResult{Author: canonical, MergedAuthorIDs: duplicateIDs, ArticlesMoved: moved}, nil
+}
+
+// RepairNameKeys brings the stored names and name keys of authors in line with NormalizeName and NameKey,
+// for keys written by migrations in SQL. Authors whose names turn out to share a key are merged into the
+// earliest created of them. It returns how many authors were merged or had their name key rewritten.
+func RepairNameKeys(ctx context.Context, repo Repository) (int, error) {
+	names, err := repo.GetStoredNames(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load author names: %w", err)
+	}
+
+	var keys []string
+	byKey := map[string][]*StoredName{}
+	for _, name := range names {
+		key := NameKey(name.Name)
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], name)
+	}
+
+	repaired := 0
+	var stale []string
+	for _, key := range keys {
+		canonical, duplicates := byKey[key][0], byKey[key][1:]
+		if len(duplicates) > 0 {
+			duplicateIDs := make([]string, 0, len(duplicates))
+			for _, duplicate := range duplicates {
+				duplicateIDs = append(duplicateIDs, duplicate.AuthorID)
+			}
+			if _, err := repo.MergeAuthors(ctx, canonical.AuthorID, duplicateIDs); err != nil {
+				return repaired, fmt.Errorf("failed to merge authors named %q: %w", canonical.Name, err)
+			}
+			repaired += len(duplicateIDs)
+		}
+		if canonical.NameKey != key || canonical.Name != NormalizeName(canonical.Name) {
+			stale = append(stale, canonical.AuthorID)
+		}
+	}
+
+	// A key can still be held by another author whose own key is being rewritten, so those wait for the next pass
+	for len(stale) > 0 {
+		var blocked []string
+		for _, id := range stale {
+			stored, err := repo.GetAuthorByID(ctx, id)
+			if err != nil {
+				return repaired, fmt.Errorf("failed to get author %s: %w", id, err)
+			}
+			stored.Name = NormalizeName(stored.Name)
+			if _, err := repo.UpdateAuthor(ctx, stored); err != nil {
+				if errors.Is(err, ErrAuthorExists) {
+					blocked = append(blocked, id)
+					continue
+				}
+				return repaired, fmt.Errorf("failed to rewrite the name key of author %s: %w", id, err)
+			}
+			repaired++
+		}
+		if len(blocked) == len(stale) {
+			return repaired, fmt.Errorf("%w: authors %v hold each other's name keys", ErrAuthorExists, blocked)
+		}
+		stale = blocked
+	}
+
+	return repaired, nil
+}
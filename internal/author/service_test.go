@@ -45,9 +45,7 @@ func TestGetOrCreateAuthor_CreateFails(t *testing.T) {
 	svc := author.NewAuthorService(mockRepo)
 
 	mockRepo.On("GetAuthorByName", mock.Anything, "Bara").Return(nil, sql.ErrNoRows)
-	mockRepo.On("CreateAuthor", mock.Anything, mock.MatchedBy(func(a *author.Author) bool {
-		return a.Name == "Bara"
-	})).Return(nil, errors.New("insert failed"))
+	mockRepo.On("UpsertAuthor", mock.Anything, "Bara").Return(nil, errors.New("insert failed"))
 
 	result, err := svc.GetOrCreateAuthor(context.Background(), "Bara")
 
@@ -63,9 +61,7 @@ func TestGetOrCreateAuthor_CreatesNew(t *testing.T) {
 	newAuthor := &author.Author{ID: "auth-2", Name: "Bara"}
 
 	mockRepo.On("GetAuthorByName", mock.Anything, "Bara").Return(nil, sql.ErrNoRows)
-	mockRepo.On("CreateAuthor", mock.Anything, mock.MatchedBy(func(a *author.Author) bool {
-		return a.Name == "Bara"
-	})).Return(newAuthor, nil)
+	mockRepo.On("UpsertAuthor", mock.Anything, "Bara").Return(newAuthor, nil)
 
 	result, err := svc.GetOrCreateAuthor(context.Background(), "Bara")
 
@@ -73,3 +69,218 @@ func TestGetOrCreateAuthor_CreatesNew(t *testing.T) {
 	assert.Equal(t, newAuthor, result)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetAuthors_DefaultsAndCapsPaging(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("GetAuthors", mock.Anything, 100, 100).Return([]*author.Author{}, int64(120), nil)
+
+	result, err := svc.GetAuthors(context.Background(), 2, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Page)
+	assert.Equal(t, 100, result.Limit)
+	assert.Equal(t, int64(120), result.Total)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetAuthorByID_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("GetAuthorByID", mock.Anything, "auth-1").Return(nil, sql.ErrNoRows)
+
+	result, err := svc.GetAuthorByID(context.Background(), "auth-1")
+
+	assert.ErrorIs(t, err, author.ErrAuthorNotFound)
+	assert.Nil(t, result)
+}
+
+func TestCreateAuthor_NameTaken(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("CreateAuthor", mock.Anything, &author.Author{Name: "Bara"}).Return(nil, author.ErrAuthorExists)
+
+	result, err := svc.CreateAuthor(context.Background(), &author.CreateAuthorRequest{Name: "Bara"})
+
+	assert.ErrorIs(t, err, author.ErrAuthorExists)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetAuthorBySlug_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("GetAuthorBySlug", mock.Anything, "bara").Return(nil, sql.ErrNoRows)
+
+	result, err := svc.GetAuthorBySlug(context.Background(), "bara")
+
+	assert.ErrorIs(t, err, author.ErrAuthorNotFound)
+	assert.Nil(t, result)
+}
+
+func TestCreateAuthor_PassesProfile(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	profile := &author.Author{
+		Name:          "Bara Putra",
+		Slug:          "bara",
+		Bio:           "Jurnalis politik.",
+		AvatarURL:     "https://cdn.example.com/bara.png",
+		SocialHandles: author.SocialHandles{Twitter: "baraputra"},
+	}
+	mockRepo.On("CreateAuthor", mock.Anything, profile).Return(nil, author.ErrSlugExists)
+
+	result, err := svc.CreateAuthor(context.Background(), &author.CreateAuthorRequest{
+		Name:          " Bara  Putra ",
+		Slug:          "bara",
+		Bio:           "Jurnalis politik.",
+		AvatarURL:     "https://cdn.example.com/bara.png",
+		SocialHandles: author.SocialHandles{Twitter: "baraputra"},
+	})
+
+	assert.ErrorIs(t, err, author.ErrSlugExists)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateAuthor_NameTakenByAnother(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("UpdateAuthor", mock.Anything, &author.Author{ID: "auth-1", Name: "Sekar"}).Return(nil, author.ErrAuthorExists)
+
+	result, err := svc.UpdateAuthor(context.Background(), "auth-1", &author.UpdateAuthorRequest{Name: "Sekar"})
+
+	assert.ErrorIs(t, err, author.ErrAuthorExists)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateAuthor_UnknownAuthor(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("UpdateAuthor", mock.Anything, &author.Author{ID: "auth-1", Name: "Bara Putra"}).Return(nil, sql.ErrNoRows)
+
+	result, err := svc.UpdateAuthor(context.Background(), "auth-1", &author.UpdateAuthorRequest{Name: "Bara Putra"})
+
+	assert.ErrorIs(t, err, author.ErrAuthorNotFound)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteAuthor_Errors(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("DeleteAuthor", mock.Anything, "missing").Return(sql.ErrNoRows)
+	mockRepo.On("DeleteAuthor", mock.Anything, "busy").Return(author.ErrAuthorHasArticles)
+	mockRepo.On("DeleteAuthor", mock.Anything, "broken").Return(errors.New("db unavailable"))
+
+	assert.ErrorIs(t, svc.DeleteAuthor(context.Background(), "missing"), author.ErrAuthorNotFound)
+	assert.ErrorIs(t, svc.DeleteAuthor(context.Background(), "busy"), author.ErrAuthorHasArticles)
+	assert.ErrorIs(t, svc.DeleteAuthor(context.Background(), "broken"), author.ErrInternalDBError)
+}
+
+func TestGetOrCreateAuthor_NormalizesName(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("GetAuthorByName", mock.Anything, "Budi Santoso").Return(nil, sql.ErrNoRows)
+	mockRepo.On("UpsertAuthor", mock.Anything, "Budi Santoso").Return(&author.Author{ID: "auth-1", Name: "Budi Santoso"}, nil)
+
+	result, err := svc.GetOrCreateAuthor(context.Background(), " Budi  Santoso ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Budi Santoso", result.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMergeAuthors_Success(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	canonical := &author.Author{ID: "auth-1", Name: "Budi Santoso"}
+	mockRepo.On("MergeAuthors", mock.Anything, "auth-1", []string{"auth-2", "auth-3"}).Return(int64(4), nil)
+	mockRepo.On("GetAuthorByID", mock.Anything, "auth-1").Return(canonical, nil)
+
+	result, err := svc.MergeAuthors(context.Background(), "auth-1", &author.MergeAuthorsRequest{DuplicateIDs: []string{"auth-2", "auth-3", "auth-2"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &author.MergeResult{Author: canonical, MergedAuthorIDs: []string{"auth-2", "auth-3"}, ArticlesMoved: 4}, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMergeAuthors_InvalidRequests(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	for _, ids := range [][]string{nil, {"auth-2", "auth-1"}} {
+		result, err := svc.MergeAuthors(context.Background(), "auth-1", &author.MergeAuthorsRequest{DuplicateIDs: ids})
+
+		assert.ErrorIs(t, err, author.ErrInvalidMerge)
+		assert.Nil(t, result)
+	}
+	mockRepo.AssertNotCalled(t, "MergeAuthors", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeAuthors_UnknownAuthor(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+	svc := author.NewAuthorService(mockRepo)
+
+	mockRepo.On("MergeAuthors", mock.Anything, "auth-1", []string{"auth-2"}).Return(int64(0), sql.ErrNoRows)
+
+	result, err := svc.MergeAuthors(context.Background(), "auth-1", &author.MergeAuthorsRequest{DuplicateIDs: []string{"auth-2"}})
+
+	assert.ErrorIs(t, err, author.ErrAuthorNotFound)
+	assert.Nil(t, result)
+}
+
+func TestRepairNameKeys_RewritesKeysAndMergesAuthorsSharingOne(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+
+	// Keys as migration 000009 computed them with lower() and a regex for whitespace
+	mockRepo.On("GetStoredNames", mock.Anything).Return([]*author.StoredName{
+		{AuthorID: "auth-1", Name: "Straße", NameKey: "straße"},
+		{AuthorID: "auth-2", Name: "Bara", NameKey: "bara"},
+		{AuthorID: "auth-3", Name: "STRASSE", NameKey: "strasse"},
+		{AuthorID: "auth-4", Name: "Budi\u00a0Santoso", NameKey: "budi\u00a0santoso"},
+	}, nil)
+	mockRepo.On("MergeAuthors", mock.Anything, "auth-1", []string{"auth-3"}).Return(int64(2), nil)
+	mockRepo.On("GetAuthorByID", mock.Anything, "auth-1").Return(&author.Author{ID: "auth-1", Name: "Straße", Slug: "strae"}, nil)
+	mockRepo.On("GetAuthorByID", mock.Anything, "auth-4").Return(&author.Author{ID: "auth-4", Name: "Budi\u00a0Santoso", Bio: "Editor"}, nil)
+	mockRepo.On("UpdateAuthor", mock.Anything, &author.Author{ID: "auth-1", Name: "Straße", Slug: "strae"}).Return(&author.Author{}, nil)
+	mockRepo.On("UpdateAuthor", mock.Anything, &author.Author{ID: "auth-4", Name: "Budi Santoso", Bio: "Editor"}).Return(&author.Author{}, nil)
+
+	repaired, err := author.RepairNameKeys(context.Background(), mockRepo)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, repaired)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetAuthorByID", mock.Anything, "auth-2")
+}
+
+func TestRepairNameKeys_RetriesKeysHeldByAnotherAuthor(t *testing.T) {
+	mockRepo := new(mocks.MockAuthorRepo)
+
+	mockRepo.On("GetStoredNames", mock.Anything).Return([]*author.StoredName{
+		{AuthorID: "auth-1", Name: "Ana", NameKey: "x"},
+		{AuthorID: "auth-2", Name: "X", NameKey: "ana"},
+	}, nil)
+	mockRepo.On("GetAuthorByID", mock.Anything, "auth-1").Return(&author.Author{ID: "auth-1", Name: "Ana"}, nil)
+	mockRepo.On("GetAuthorByID", mock.Anything, "auth-2").Return(&author.Author{ID: "auth-2", Name: "X"}, nil)
+	mockRepo.On("UpdateAuthor", mock.Anything, &author.Author{ID: "auth-1", Name: "Ana"}).Return(nil, author.ErrAuthorExists).Once()
+	mockRepo.On("UpdateAuthor", mock.Anything, &author.Author{ID: "auth-2", Name: "X"}).Return(&author.Author{}, nil).Once()
+	mockRepo.On("UpdateAuthor", mock.Anything, &author.Author{ID: "auth-1", Name: "Ana"}).Return(&author.Author{}, nil).Once()
+
+	repaired, err := author.RepairNameKeys(context.Background(), mockRepo)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, repaired)
+	mockRepo.AssertExpectations(t)
+}
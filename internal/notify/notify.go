@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"kumparan-test/internal/article"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Notifiers selectable in the configuration.
+const (
+	NotifierLog     = "log"
+	NotifierWebhook = "webhook"
+)
+
+// New returns the notifier named by kind, defaulting to NotifierLog when kind is empty.
+func New(kind, webhookURL string) (article.Notifier, error) {
+	switch kind {
+	case "", NotifierLog:
+		return NewLogNotifier(), nil
+	case NotifierWebhook:
+		if webhookURL == "" {
+			return nil, fmt.Errorf("the %s notifier needs a webhook URL", NotifierWebhook)
+		}
+		return NewWebhookNotifier(webhookURL, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q, expected %s or %s", kind, NotifierLog, NotifierWebhook)
+	}
+}
+
+type logNotifier struct{}
+
+// NewLogNotifier creates a notifier that writes every alert to the service log.
+func NewLogNotifier() article.Notifier {
+	return &logNotifier{}
+}
+
+// Notify logs the alert.
+func (n *logNotifier) Notify(ctx context.Context, alert *article.Alert) error {
+	logrus.WithFields(logrus.Fields{
+		"saved_search_id": alert.SavedSearch.ID,
+		"saved_search":    alert.SavedSearch.Name,
+		"article_id":      alert.Article.ID,
+	}).Infof("New article matches saved search: %s", alert.Article.Title)
+	return nil
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that POSTs every alert as JSON to url.
+// A nil client uses one that gives up after 10 seconds.
+func NewWebhookNotifier(url string, client *http.Client) article.Notifier {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &webhookNotifier{url: url, client: client}
+}
+
+// Notify sends the alert to the webhook. Any response other than 2xx is an error.
+func (n *webhookNotifier) Notify(ctx context.Context, alert *article.Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kumparan-test/internal/article"
+	"kumparan-test/internal/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAlert() *article.Alert {
+	return &article.Alert{
+		SavedSearch: &article.SavedSearch{ID: "search-1", Name: "Banjir", Query: "banjir jakarta"},
+		Article:     &article.Article{ID: "art-1", Title: "Banjir Jakarta"},
+	}
+}
+
+func TestWebhookNotifier_PostsAlertAsJSON(t *testing.T) {
+	var received article.Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := notify.NewWebhookNotifier(server.URL, nil).Notify(context.Background(), newAlert())
+
+	require.NoError(t, err)
+	assert.Equal(t, "search-1", received.SavedSearch.ID)
+	assert.Equal(t, "banjir jakarta", received.SavedSearch.Query)
+	assert.Equal(t, "art-1", received.Article.ID)
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := notify.NewWebhookNotifier(server.URL, nil).Notify(context.Background(), newAlert())
+
+	assert.ErrorContains(t, err, "502")
+}
+
+func TestNew(t *testing.T) {
+	n, err := notify.New("", "")
+	require.NoError(t, err)
+	assert.NoError(t, n.Notify(context.Background(), newAlert()))
+
+	_, err = notify.New(notify.NotifierWebhook, "")
+	assert.Error(t, err)
+
+	n, err = notify.New(notify.NotifierWebhook, "http://localhost/hook")
+	assert.NoError(t, err)
+	assert.NotNil(t, n)
+
+	_, err = notify.New("email", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"kumparan-test/internal/outbox"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepo struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*outbox.Event, error) {
+	args := m.Called(ctx, limit, lease)
+	if events := args.Get(0); events != nil {
+		return events.([]*outbox.Event), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOutboxRepo) MarkProcessed(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepo) MarkFailed(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	args := m.Called(ctx, id, attempts, nextAttemptAt, lastError)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepo) MarkDead(ctx context.Context, id int64, attempts int, lastError string) error {
+	args := m.Called(ctx, id, attempts, lastError)
+	return args.Error(0)
+}
+
+type MockHandler struct {
+	mock.Mock
+}
+
+func (m *MockHandler) Handle(ctx context.Context, event *outbox.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
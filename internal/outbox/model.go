@@ -0,0 +1,21 @@
+package outbox
+
+import "time"
+
+const (
+	StatusPending   = "pending"
+	StatusProcessed = "processed"
+	StatusDead      = "dead"
+)
+
+// Event represents a change that must be propagated outside of PostgreSQL,
+// written in the same transaction as the change itself.
+type Event struct {
+	ID          int64
+	AggregateID string
+	EventType   string
+	Status      string
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time
+}
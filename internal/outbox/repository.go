@@ -0,0 +1,89 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+)
+
+type Repository interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*Event, error)
+	MarkProcessed(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkDead(ctx context.Context, id int64, attempts int, lastError string) error
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL outbox repository.
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+// Enqueue writes an event using the caller's transaction, so the event is only
+// visible if the change that produced it is committed.
+func Enqueue(ctx context.Context, tx *sql.Tx, event *Event) error {
+	query := `INSERT INTO outbox_events (aggregate_id, event_type) VALUES ($1, $2)`
+	_, err := tx.ExecContext(ctx, query, event.AggregateID, event.EventType)
+	return err
+}
+
+// ClaimPending picks up to limit due events and pushes their next attempt out by lease,
+// so other workers skip them while they are being processed.
+func (r *postgresRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*Event, error) {
+	now := time.Now()
+
+	query := `UPDATE outbox_events SET next_attempt_at = $1 `
+	query += `WHERE id IN (`
+	query += `SELECT id FROM outbox_events WHERE status = 'pending' AND next_attempt_at <= $2 `
+	query += `ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED`
+	query += `) RETURNING id, aggregate_id, event_type, status, attempts, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, now.Add(lease), now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*Event{}
+	for rows.Next() {
+		var event Event
+		if err := rows.Scan(&event.ID, &event.AggregateID, &event.EventType, &event.Status, &event.Attempts, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	// UPDATE ... RETURNING does not preserve the subquery order
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	return events, nil
+}
+
+// MarkProcessed flags an event as successfully delivered.
+func (r *postgresRepository) MarkProcessed(ctx context.Context, id int64) error {
+	query := `UPDATE outbox_events SET status = 'processed', processed_at = $1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	return err
+}
+
+// MarkFailed records a failed attempt and schedules the next one.
+func (r *postgresRepository) MarkFailed(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE outbox_events SET attempts = $1, next_attempt_at = $2, last_error = $3 WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, query, attempts, nextAttemptAt, lastError, id)
+	return err
+}
+
+// MarkDead moves an event to the dead-letter state; it will not be retried.
+func (r *postgresRepository) MarkDead(ctx context.Context, id int64, attempts int, lastError string) error {
+	query := `UPDATE outbox_events SET status = 'dead', attempts = $1, last_error = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, attempts, lastError, id)
+	return err
+}
@@ -0,0 +1,114 @@
+package outbox_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kumparan-test/internal/outbox"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRepoWithMock(t *testing.T) (outbox.Repository, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	return outbox.NewPostgresRepository(db), mock, func() { db.Close() }
+}
+
+func TestEnqueue_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO outbox_events \(aggregate_id, event_type\) VALUES \(\$1, \$2\)`).
+		WithArgs("article-1", "article.created").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	err = outbox.Enqueue(context.Background(), tx, &outbox.Event{AggregateID: "article-1", EventType: "article.created"})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimPending_ReturnsEventsInOrder(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "aggregate_id", "event_type", "status", "attempts", "created_at"}).
+		AddRow(int64(7), "article-2", "article.updated", "pending", 1, now).
+		AddRow(int64(3), "article-1", "article.created", "pending", 0, now)
+
+	mock.ExpectQuery(`UPDATE outbox_events SET next_attempt_at = \$1 WHERE id IN \(SELECT id FROM outbox_events WHERE status = 'pending' AND next_attempt_at <= \$2 ORDER BY id LIMIT \$3 FOR UPDATE SKIP LOCKED\)`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 10).
+		WillReturnRows(rows)
+
+	events, err := repo.ClaimPending(context.Background(), 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(3), events[0].ID)
+	assert.Equal(t, int64(7), events[1].ID)
+	assert.Equal(t, 1, events[1].Attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimPending_DBError(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`UPDATE outbox_events`).
+		WillReturnError(assert.AnError)
+
+	events, err := repo.ClaimPending(context.Background(), 10, time.Minute)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkProcessed_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectExec(`UPDATE outbox_events SET status = 'processed', processed_at = \$1 WHERE id = \$2`).
+		WithArgs(sqlmock.AnyArg(), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkProcessed(context.Background(), 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkFailed_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	next := time.Now().Add(time.Minute)
+	mock.ExpectExec(`UPDATE outbox_events SET attempts = \$1, next_attempt_at = \$2, last_error = \$3 WHERE id = \$4`).
+		WithArgs(2, next, "es down", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkFailed(context.Background(), 3, 2, next, "es down"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkDead_Success(t *testing.T) {
+	repo, mock, cleanup := setupRepoWithMock(t)
+	defer cleanup()
+
+	mock.ExpectExec(`UPDATE outbox_events SET status = 'dead', attempts = \$1, last_error = \$2 WHERE id = \$3`).
+		WithArgs(10, "es down", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkDead(context.Background(), 3, 10, "es down"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,204 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Handler delivers a single outbox event. Returning an error schedules a retry.
+type Handler interface {
+	Handle(ctx context.Context, event *Event) error
+}
+
+// WorkerConfig controls how the worker polls and retries events.
+type WorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Lease        time.Duration
+}
+
+// Worker drains pending outbox events in the background.
+type Worker struct {
+	repo    Repository
+	handler Handler
+	cfg     WorkerConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+}
+
+// NewWorker creates a new outbox worker. Zero config values fall back to sensible defaults.
+func NewWorker(repo Repository, handler Handler, cfg WorkerConfig) *Worker {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Minute
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{
+		repo:    repo,
+		handler: handler,
+		cfg:     cfg,
+		ctx:     ctx,
+		cancel:  cancel,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start begins polling for events in a background goroutine.
+// It does nothing once the worker has been stopped.
+func (w *Worker) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started || w.stopped {
+		return
+	}
+	w.started = true
+
+	logrus.Info("Starting outbox worker...")
+	go w.run()
+}
+
+// Stop stops polling, waits for the in-flight batch and then drains every event
+// that is already due. Draining is abandoned when ctx expires.
+// A worker that was never started is stopped without draining.
+func (w *Worker) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	started := w.started
+	w.stopped = true
+	w.mu.Unlock()
+
+	defer w.cancel()
+	if !started {
+		return nil
+	}
+	close(w.stop)
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	logrus.Info("Draining outbox events...")
+	for {
+		processed, err := w.ProcessBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if processed == 0 {
+			logrus.Info("Outbox worker stopped")
+			return nil
+		}
+	}
+}
+
+func (w *Worker) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := w.ProcessBatch(w.ctx)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to process outbox batch")
+		}
+
+		// Keep going without waiting while there is a backlog
+		if err == nil && processed == w.cfg.BatchSize {
+			select {
+			case <-w.stop:
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch claims and handles one batch of due events, returning how many were claimed.
+func (w *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	events, err := w.repo.ClaimPending(ctx, w.cfg.BatchSize, w.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		fields := logrus.Fields{
+			"event_id":     event.ID,
+			"event_type":   event.EventType,
+			"aggregate_id": event.AggregateID,
+		}
+
+		handleErr := w.handler.Handle(ctx, event)
+		if handleErr == nil {
+			if err := w.repo.MarkProcessed(ctx, event.ID); err != nil {
+				logrus.WithError(err).WithFields(fields).Error("Failed to mark outbox event as processed")
+			}
+			continue
+		}
+
+		attempts := event.Attempts + 1
+		if attempts >= w.cfg.MaxAttempts {
+			logrus.WithError(handleErr).WithFields(fields).Errorf("Outbox event failed %d times, moving to dead-letter", attempts)
+			if err := w.repo.MarkDead(ctx, event.ID, attempts, handleErr.Error()); err != nil {
+				logrus.WithError(err).WithFields(fields).Error("Failed to mark outbox event as dead")
+			}
+			continue
+		}
+
+		nextAttemptAt := time.Now().Add(w.backoff(attempts))
+		logrus.WithError(handleErr).WithFields(fields).Warnf("Outbox event failed, retrying at %s", nextAttemptAt.Format(time.RFC3339))
+		if err := w.repo.MarkFailed(ctx, event.ID, attempts, nextAttemptAt, handleErr.Error()); err != nil {
+			logrus.WithError(err).WithFields(fields).Error("Failed to reschedule outbox event")
+		}
+	}
+
+	return len(events), nil
+}
+
+// backoff returns the exponential delay before the given attempt, capped at MaxBackoff.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= w.cfg.MaxBackoff {
+			return w.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
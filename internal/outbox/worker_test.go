@@ -0,0 +1,129 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kumparan-test/internal/outbox"
+	"kumparan-test/internal/outbox/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessBatch_MarksProcessed(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepo)
+	mockHandler := new(mocks.MockHandler)
+	worker := outbox.NewWorker(mockRepo, mockHandler, outbox.WorkerConfig{BatchSize: 5})
+
+	event := &outbox.Event{ID: 1, AggregateID: "article-1", EventType: "article.created"}
+	mockRepo.On("ClaimPending", mock.Anything, 5, time.Minute).Return([]*outbox.Event{event}, nil)
+	mockHandler.On("Handle", mock.Anything, event).Return(nil)
+	mockRepo.On("MarkProcessed", mock.Anything, int64(1)).Return(nil)
+
+	processed, err := worker.ProcessBatch(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	mockRepo.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestProcessBatch_FailureSchedulesRetryWithBackoff(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepo)
+	mockHandler := new(mocks.MockHandler)
+	worker := outbox.NewWorker(mockRepo, mockHandler, outbox.WorkerConfig{
+		BatchSize:   5,
+		MaxAttempts: 5,
+		BaseBackoff: time.Second,
+		MaxBackoff:  time.Minute,
+	})
+
+	event := &outbox.Event{ID: 1, AggregateID: "article-1", EventType: "article.created", Attempts: 2}
+	mockRepo.On("ClaimPending", mock.Anything, 5, time.Minute).Return([]*outbox.Event{event}, nil)
+	mockHandler.On("Handle", mock.Anything, event).Return(errors.New("es down"))
+
+	before := time.Now()
+	mockRepo.On("MarkFailed", mock.Anything, int64(1), 3, mock.MatchedBy(func(next time.Time) bool {
+		// Third attempt waits BaseBackoff * 2^2
+		return !next.Before(before.Add(4*time.Second)) && next.Before(time.Now().Add(5*time.Second))
+	}), "es down").Return(nil)
+
+	_, err := worker.ProcessBatch(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "MarkProcessed", mock.Anything, mock.Anything)
+}
+
+func TestProcessBatch_ExhaustedAttemptsGoToDeadLetter(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepo)
+	mockHandler := new(mocks.MockHandler)
+	worker := outbox.NewWorker(mockRepo, mockHandler, outbox.WorkerConfig{BatchSize: 5, MaxAttempts: 3})
+
+	event := &outbox.Event{ID: 1, AggregateID: "article-1", EventType: "article.created", Attempts: 2}
+	mockRepo.On("ClaimPending", mock.Anything, 5, time.Minute).Return([]*outbox.Event{event}, nil)
+	mockHandler.On("Handle", mock.Anything, event).Return(errors.New("es down"))
+	mockRepo.On("MarkDead", mock.Anything, int64(1), 3, "es down").Return(nil)
+
+	_, err := worker.ProcessBatch(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessBatch_ClaimFails(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepo)
+	mockHandler := new(mocks.MockHandler)
+	worker := outbox.NewWorker(mockRepo, mockHandler, outbox.WorkerConfig{BatchSize: 5})
+
+	mockRepo.On("ClaimPending", mock.Anything, 5, time.Minute).Return(nil, errors.New("db down"))
+
+	processed, err := worker.ProcessBatch(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, processed)
+	mockHandler.AssertNotCalled(t, "Handle", mock.Anything, mock.Anything)
+}
+
+func TestStop_DrainsDueEvents(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepo)
+	mockHandler := new(mocks.MockHandler)
+	worker := outbox.NewWorker(mockRepo, mockHandler, outbox.WorkerConfig{BatchSize: 5, PollInterval: time.Hour})
+
+	event := &outbox.Event{ID: 1, AggregateID: "article-1", EventType: "article.created"}
+
+	// First poll happens on start, the event shows up only while shutting down
+	polled := make(chan struct{})
+	mockRepo.On("ClaimPending", mock.Anything, 5, time.Minute).Return([]*outbox.Event{}, nil).Once().
+		Run(func(mock.Arguments) { close(polled) })
+	mockRepo.On("ClaimPending", mock.Anything, 5, time.Minute).Return([]*outbox.Event{event}, nil).Once()
+	mockRepo.On("ClaimPending", mock.Anything, 5, time.Minute).Return([]*outbox.Event{}, nil).Once()
+	mockHandler.On("Handle", mock.Anything, event).Return(nil)
+	mockRepo.On("MarkProcessed", mock.Anything, int64(1)).Return(nil)
+
+	worker.Start()
+	<-polled
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, worker.Stop(ctx))
+	mockRepo.AssertExpectations(t)
+	mockHandler.AssertExpectations(t)
+}
+
+func TestStop_BeforeStartDoesNotDrain(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepo)
+	mockHandler := new(mocks.MockHandler)
+	worker := outbox.NewWorker(mockRepo, mockHandler, outbox.WorkerConfig{})
+
+	assert.NoError(t, worker.Stop(context.Background()))
+
+	// Starting after Stop must not bring the worker back
+	worker.Start()
+	mockRepo.AssertNotCalled(t, "ClaimPending", mock.Anything, mock.Anything, mock.Anything)
+}
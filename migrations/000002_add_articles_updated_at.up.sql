@@ -0,0 +1,4 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+-- Existing rows have never been edited, so their last update is their creation
+UPDATE articles SET updated_at = created_at;
@@ -0,0 +1,5 @@
+-- Drop the index on pending outbox events
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+
+-- Drop the outbox table
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,2 @@
+-- Drop the keyset pagination index
+DROP INDEX IF EXISTS idx_articles_created_at_id;
@@ -0,0 +1,10 @@
+-- Full-text search over title and body, used when Elasticsearch is unavailable.
+-- The 'simple' configuration only lowercases, so it works for any language.
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(body, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN (search_vector);
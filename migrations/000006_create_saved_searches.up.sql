@@ -0,0 +1,8 @@
+-- Search queries subscribed to for new-article alerts. Elasticsearch holds a percolator copy of
+-- each query, registered through the outbox and rebuilt from this table when its index is replaced.
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name       TEXT NOT NULL,
+    query      TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
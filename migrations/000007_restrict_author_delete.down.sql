@@ -0,0 +1,6 @@
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS fk_author;
+ALTER TABLE articles
+    ADD CONSTRAINT fk_author
+        FOREIGN KEY (author_id)
+        REFERENCES authors(id)
+        ON DELETE CASCADE;
@@ -0,0 +1,7 @@
+-- Deleting an author must not silently delete their articles: it is refused while any article references them.
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS fk_author;
+ALTER TABLE articles
+    ADD CONSTRAINT fk_author
+        FOREIGN KEY (author_id)
+        REFERENCES authors(id)
+        ON DELETE RESTRICT;
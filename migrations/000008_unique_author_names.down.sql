@@ -0,0 +1,2 @@
+-- Merged duplicate authors are not restored
+ALTER TABLE authors DROP CONSTRAINT IF EXISTS authors_name_key;
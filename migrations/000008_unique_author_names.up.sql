@@ -0,0 +1,35 @@
+-- Concurrent posts by a new author could create several rows with the same name. Each group keeps the author with
+-- the earliest article (or the lowest id when none has articles), and the articles of the others are moved to it.
+CREATE TEMPORARY TABLE author_duplicates AS
+SELECT id AS duplicate_id, canonical_id
+FROM (
+    SELECT authors.id,
+           first_value(authors.id) OVER (
+               PARTITION BY authors.name
+               ORDER BY first_article.created_at NULLS LAST, authors.id
+           ) AS canonical_id
+    FROM authors
+    LEFT JOIN LATERAL (
+        SELECT MIN(created_at) AS created_at FROM articles WHERE articles.author_id = authors.id
+    ) first_article ON true
+) ranked
+WHERE id <> canonical_id;
+
+-- Moved articles are re-indexed under their new author by the outbox worker
+INSERT INTO outbox_events (aggregate_id, event_type)
+SELECT articles.id, 'article.updated'
+FROM articles
+JOIN author_duplicates ON articles.author_id = author_duplicates.duplicate_id;
+
+UPDATE articles
+SET author_id = author_duplicates.canonical_id, updated_at = CURRENT_TIMESTAMP
+FROM author_duplicates
+WHERE articles.author_id = author_duplicates.duplicate_id;
+
+DELETE FROM authors
+USING author_duplicates
+WHERE authors.id = author_duplicates.duplicate_id;
+
+DROP TABLE author_duplicates;
+
+ALTER TABLE authors ADD CONSTRAINT authors_name_key UNIQUE (name);
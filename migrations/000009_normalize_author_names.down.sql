@@ -0,0 +1,4 @@
+-- Tidied names and merged authors are not restored
+ALTER TABLE authors DROP CONSTRAINT IF EXISTS authors_name_key_unique;
+ALTER TABLE authors DROP COLUMN IF EXISTS name_key;
+ALTER TABLE authors ADD CONSTRAINT authors_name_key UNIQUE (name);
@@ -0,0 +1,59 @@
+-- Authors are matched on name_key, computed by author.NameKey. SQL can only approximate it (lower() is not case
+-- folding and depends on the locale), so --migrate recomputes every key in Go after the migrations have run and
+-- merges the authors whose keys turn out to be the same.
+ALTER TABLE authors DROP CONSTRAINT IF EXISTS authors_name_key;
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS name_key TEXT;
+
+-- Untidy names are tidied, and the articles of their authors re-indexed with the new spelling
+CREATE TEMPORARY TABLE author_names AS
+SELECT id, regexp_replace(regexp_replace(normalize(name, NFC), '^\s+|\s+$', '', 'g'), '\s+', ' ', 'g') AS name
+FROM authors;
+
+INSERT INTO outbox_events (aggregate_id, event_type)
+SELECT authors.id, 'author.updated'
+FROM authors
+JOIN author_names ON authors.id = author_names.id
+WHERE authors.name <> author_names.name;
+
+UPDATE authors
+SET name = author_names.name, name_key = lower(author_names.name)
+FROM author_names
+WHERE authors.id = author_names.id;
+
+DROP TABLE author_names;
+
+-- Authors whose names only differed in case or spacing are merged into the one with the earliest article,
+-- as in 000008
+CREATE TEMPORARY TABLE author_duplicates AS
+SELECT id AS duplicate_id, canonical_id
+FROM (
+    SELECT authors.id,
+           first_value(authors.id) OVER (
+               PARTITION BY authors.name_key
+               ORDER BY first_article.created_at NULLS LAST, authors.id
+           ) AS canonical_id
+    FROM authors
+    LEFT JOIN LATERAL (
+        SELECT MIN(created_at) AS created_at FROM articles WHERE articles.author_id = authors.id
+    ) first_article ON true
+) ranked
+WHERE id <> canonical_id;
+
+INSERT INTO outbox_events (aggregate_id, event_type)
+SELECT articles.id, 'article.updated'
+FROM articles
+JOIN author_duplicates ON articles.author_id = author_duplicates.duplicate_id;
+
+UPDATE articles
+SET author_id = author_duplicates.canonical_id, updated_at = CURRENT_TIMESTAMP
+FROM author_duplicates
+WHERE articles.author_id = author_duplicates.duplicate_id;
+
+DELETE FROM authors
+USING author_duplicates
+WHERE authors.id = author_duplicates.duplicate_id;
+
+DROP TABLE author_duplicates;
+
+ALTER TABLE authors ALTER COLUMN name_key SET NOT NULL;
+ALTER TABLE authors ADD CONSTRAINT authors_name_key_unique UNIQUE (name_key);
@@ -0,0 +1,8 @@
+ALTER TABLE authors DROP CONSTRAINT IF EXISTS authors_slug_key;
+ALTER TABLE authors
+    DROP COLUMN IF EXISTS slug,
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS social_handles,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS updated_at;
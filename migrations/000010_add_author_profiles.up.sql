@@ -0,0 +1,33 @@
+ALTER TABLE authors
+    ADD COLUMN IF NOT EXISTS slug TEXT,
+    ADD COLUMN IF NOT EXISTS bio TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS avatar_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS social_handles JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Existing authors were created with their first article
+UPDATE authors
+SET created_at = first_article.created_at, updated_at = first_article.created_at
+FROM (SELECT author_id, MIN(created_at) AS created_at FROM articles GROUP BY author_id) first_article
+WHERE authors.id = first_article.author_id;
+
+-- Slugs are derived from names like author.Slugify does, and numbered from the second author sharing one
+WITH derived AS (
+    SELECT id, created_at, COALESCE(NULLIF(
+        trim(BOTH '-' FROM regexp_replace(
+            lower(regexp_replace(normalize(name, NFD), '[\u0300-\u036f]', '', 'g')),
+            '[^a-z0-9]+', '-', 'g')),
+        ''), 'author') AS base
+    FROM authors
+), numbered AS (
+    SELECT id, base, ROW_NUMBER() OVER (PARTITION BY base ORDER BY created_at, id) AS n
+    FROM derived
+)
+UPDATE authors
+SET slug = CASE WHEN numbered.n = 1 THEN numbered.base ELSE numbered.base || '-' || numbered.n END
+FROM numbered
+WHERE authors.id = numbered.id;
+
+ALTER TABLE authors ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE authors ADD CONSTRAINT authors_slug_key UNIQUE (slug);
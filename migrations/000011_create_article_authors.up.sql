@@ -0,0 +1,19 @@
+-- Bylines: every author credited on an article, in credit order, with their role. articles.author_id is
+-- kept as the lead (first) byline. Deleting an article removes its bylines, while an author who still has
+-- bylines cannot be deleted.
+CREATE TABLE IF NOT EXISTS article_authors (
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    author_id  UUID NOT NULL REFERENCES authors(id) ON DELETE RESTRICT,
+    role       TEXT NOT NULL DEFAULT 'writer' CHECK (role IN ('writer', 'editor', 'photographer')),
+    position   INT  NOT NULL,
+
+    PRIMARY KEY (article_id, author_id),
+    UNIQUE (article_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_authors_author_id ON article_authors(author_id);
+
+-- Every existing article was written by its single author
+INSERT INTO article_authors (article_id, author_id, role, position)
+SELECT id, author_id, 'writer', 1 FROM articles
+ON CONFLICT DO NOTHING;
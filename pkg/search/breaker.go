@@ -0,0 +1,196 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrCircuitOpen is returned without contacting Elasticsearch while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("search circuit breaker is open")
+
+// BreakerConfig controls when the circuit breaker opens and how long it stays open.
+type BreakerConfig struct {
+	FailureThreshold int           // Consecutive unavailability errors that open the circuit
+	Cooldown         time.Duration // How long the circuit stays open before a trial call
+}
+
+// circuitBreaker stops calling Elasticsearch after repeated unavailability errors, so
+// callers fail fast and can fall back instead of waiting on timeouts.
+// After the cooldown a single trial call is let through; its outcome closes or re-opens the circuit.
+type circuitBreaker struct {
+	next SearchService
+	cfg  BreakerConfig
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+// NewCircuitBreaker wraps a SearchService with a circuit breaker. Zero config values fall back to sensible defaults.
+func NewCircuitBreaker(next SearchService, cfg BreakerConfig) SearchService {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	return &circuitBreaker{next: next, cfg: cfg}
+}
+
+// IsUnavailable reports whether err means Elasticsearch could not serve the request at all,
+// as opposed to rejecting it (e.g. a malformed query). A request cut short by its context
+// counts too; the circuit breaker tells those apart from outages by the caller's context.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, elastic.ErrNoClient) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var esErr *elastic.Error
+	if errors.As(err, &esErr) {
+		return esErr.Status >= http.StatusInternalServerError ||
+			esErr.Status == http.StatusRequestTimeout ||
+			esErr.Status == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// allow reports whether a call may go through.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.cfg.FailureThreshold {
+		return true
+	}
+	if b.trial || time.Now().Before(b.openUntil) {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// record updates the breaker with the outcome of a call.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.failures >= b.cfg.FailureThreshold
+	b.trial = false
+
+	if !IsUnavailable(err) {
+		if wasOpen {
+			logrus.Info("Elasticsearch is available again, closing search circuit breaker")
+		}
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.cfg.FailureThreshold {
+		b.openUntil = time.Now().Add(b.cfg.Cooldown)
+		if !wasOpen {
+			logrus.WithError(err).Warnf("Elasticsearch unavailable, opening search circuit breaker for %s", b.cfg.Cooldown)
+		}
+	}
+}
+
+// release lets another call be the trial without recording an outcome.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// call runs fn through the breaker. A call that fails once the caller's context is done, such as
+// an autocomplete search running out of its short time budget, says nothing about Elasticsearch
+// and is not recorded.
+func (b *circuitBreaker) call(ctx context.Context, fn func() error) error {
+	if !b.allow() {
+		return ErrCircuitOpen
+	}
+	err := fn()
+	if err != nil && ctx.Err() != nil {
+		b.release()
+		return err
+	}
+	b.record(err)
+	return err
+}
+
+func (b *circuitBreaker) IndexDocument(ctx context.Context, indexName string, id string, doc interface{}) error {
+	return b.call(ctx, func() error { return b.next.IndexDocument(ctx, indexName, id, doc) })
+}
+
+func (b *circuitBreaker) UpdateDocument(ctx context.Context, indexName string, id string, doc interface{}) error {
+	return b.call(ctx, func() error { return b.next.UpdateDocument(ctx, indexName, id, doc) })
+}
+
+func (b *circuitBreaker) DeleteDocument(ctx context.Context, indexName string, id string) error {
+	return b.call(ctx, func() error { return b.next.DeleteDocument(ctx, indexName, id) })
+}
+
+func (b *circuitBreaker) SearchDocuments(ctx context.Context, indexName string, query Query, opts SearchOptions) (*SearchResult, error) {
+	var result *SearchResult
+	err := b.call(ctx, func() (err error) {
+		result, err = b.next.SearchDocuments(ctx, indexName, query, opts)
+		return err
+	})
+	return result, err
+}
+
+func (b *circuitBreaker) Percolate(ctx context.Context, indexName string, doc interface{}) ([]string, error) {
+	var ids []string
+	err := b.call(ctx, func() (err error) {
+		ids, err = b.next.Percolate(ctx, indexName, doc)
+		return err
+	})
+	return ids, err
+}
+
+func (b *circuitBreaker) BulkIndexDocuments(ctx context.Context, indexName string, docs map[string]interface{}) error {
+	return b.call(ctx, func() error { return b.next.BulkIndexDocuments(ctx, indexName, docs) })
+}
+
+func (b *circuitBreaker) IndexExists(ctx context.Context, indexName string) (bool, error) {
+	var exists bool
+	err := b.call(ctx, func() (err error) {
+		exists, err = b.next.IndexExists(ctx, indexName)
+		return err
+	})
+	return exists, err
+}
+
+func (b *circuitBreaker) CreateIndex(ctx context.Context, indexName string, body string) error {
+	return b.call(ctx, func() error { return b.next.CreateIndex(ctx, indexName, body) })
+}
+
+func (b *circuitBreaker) DeleteIndex(ctx context.Context, indexName string) error {
+	return b.call(ctx, func() error { return b.next.DeleteIndex(ctx, indexName) })
+}
+
+func (b *circuitBreaker) SwitchAlias(ctx context.Context, alias string, indexName string) ([]string, error) {
+	var previous []string
+	err := b.call(ctx, func() (err error) {
+		previous, err = b.next.SwitchAlias(ctx, alias, indexName)
+		return err
+	})
+	return previous, err
+}
+
+func (b *circuitBreaker) Close() {
+	b.next.Close()
+}
@@ -0,0 +1,107 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"kumparan-test/pkg/search"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSearchService answers every search with err and counts the calls that got through.
+type stubSearchService struct {
+	search.SearchService
+	err   error
+	calls int
+}
+
+func (s *stubSearchService) SearchDocuments(ctx context.Context, indexName string, query search.Query, opts search.SearchOptions) (*search.SearchResult, error) {
+	s.calls++
+	return &search.SearchResult{}, s.err
+}
+
+func TestCircuitBreaker_OpensAfterThresholdAndRecovers(t *testing.T) {
+	stub := &stubSearchService{err: fmt.Errorf("failed to perform search: %w", elastic.ErrNoClient)}
+	breaker := search.NewCircuitBreaker(stub, search.BreakerConfig{FailureThreshold: 2, Cooldown: 20 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		_, err := breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+		assert.ErrorIs(t, err, elastic.ErrNoClient)
+	}
+
+	_, err := breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+	assert.ErrorIs(t, err, search.ErrCircuitOpen)
+	assert.Equal(t, 2, stub.calls)
+
+	// After the cooldown one trial call goes through and closes the circuit
+	time.Sleep(30 * time.Millisecond)
+	stub.err = nil
+	_, err = breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+	assert.NoError(t, err)
+	_, err = breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, stub.calls)
+}
+
+func TestCircuitBreaker_FailedTrialReopens(t *testing.T) {
+	stub := &stubSearchService{err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	breaker := search.NewCircuitBreaker(stub, search.BreakerConfig{FailureThreshold: 1, Cooldown: 20 * time.Millisecond})
+
+	_, _ = breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+	time.Sleep(30 * time.Millisecond)
+	_, err := breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, search.ErrCircuitOpen)
+
+	_, err = breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+	assert.ErrorIs(t, err, search.ErrCircuitOpen)
+	assert.Equal(t, 2, stub.calls)
+}
+
+func TestCircuitBreaker_RejectedRequestsDoNotOpen(t *testing.T) {
+	stub := &stubSearchService{err: &elastic.Error{Status: 400}}
+	breaker := search.NewCircuitBreaker(stub, search.BreakerConfig{FailureThreshold: 1})
+
+	for i := 0; i < 3; i++ {
+		_, err := breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+		assert.NotErrorIs(t, err, search.ErrCircuitOpen)
+	}
+	assert.Equal(t, 3, stub.calls)
+}
+
+func TestCircuitBreaker_CallerDeadlinesDoNotOpen(t *testing.T) {
+	stub := &stubSearchService{err: fmt.Errorf("failed to perform search: %w", context.DeadlineExceeded)}
+	breaker := search.NewCircuitBreaker(stub, search.BreakerConfig{FailureThreshold: 1})
+
+	// Like an autocomplete search whose budget ran out while Elasticsearch was slow
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	for i := 0; i < 3; i++ {
+		_, err := breaker.SearchDocuments(ctx, search.ArticleIndexName, nil, search.SearchOptions{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+
+	// A deadline of Elasticsearch itself, with the caller still waiting, still counts
+	_, err := breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = breaker.SearchDocuments(context.Background(), search.ArticleIndexName, nil, search.SearchOptions{})
+	assert.ErrorIs(t, err, search.ErrCircuitOpen)
+	assert.Equal(t, 4, stub.calls)
+}
+
+func TestIsUnavailable(t *testing.T) {
+	assert.False(t, search.IsUnavailable(nil))
+	assert.False(t, search.IsUnavailable(errors.New("boom")))
+	assert.False(t, search.IsUnavailable(&elastic.Error{Status: 400}))
+	assert.True(t, search.IsUnavailable(&elastic.Error{Status: 503}))
+	assert.True(t, search.IsUnavailable(&elastic.Error{Status: 429}))
+	assert.True(t, search.IsUnavailable(fmt.Errorf("wrapped: %w", search.ErrCircuitOpen)))
+	assert.True(t, search.IsUnavailable(context.DeadlineExceeded))
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Field names of ArticleDocument, for use in queries and sorts.
+const (
+	ArticleFieldID        = "id"
+	ArticleFieldTitle     = "title"
+	ArticleFieldBody      = "body"
+	ArticleFieldAuthor    = "author"
+	ArticleFieldAuthorID  = "author_id"
+	ArticleFieldAuthorKey = "author_key"
+	ArticleFieldCreatedAt = "created_at"
+	ArticleFieldUpdatedAt = "updated_at"
+	ArticleFieldChecksum  = "checksum"
+)
+
+// Search-as-you-type subfields of ArticleDocument, matched by MatchPrefix queries in Elasticsearch.
+// Each also has _2gram, _3gram and _index_prefix subfields, see SuggestFields.
+const (
+	ArticleFieldTitleSuggest  = "title.suggest"
+	ArticleFieldAuthorSuggest = "author.suggest"
+)
+
+// SuggestFields returns the fields of a search_as_you_type field to match with a bool_prefix multi_match query.
+func SuggestFields(field string) []string {
+	return []string{field, field + "._2gram", field + "._3gram"}
+}
+
+// ArticleDocument is an article as stored in the articles index.
+// Its JSON field names must match the properties declared in ArticleMapping. The author fields hold
+// every author credited on the article, in credit order, as Elasticsearch indexes arrays value by value.
+// AuthorKeys are the matching keys of the author names, so that filtering by name ignores case and spacing.
+// Checksum is the ContentChecksum of the other fields, telling whether the document still matches its article.
+type ArticleDocument struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	Authors    []string  `json:"author"`
+	AuthorIDs  []string  `json:"author_id"`
+	AuthorKeys []string  `json:"author_key"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Checksum   string    `json:"checksum"`
+}
+
+// ContentChecksum returns a hash of every field of the document but Checksum. It changes whenever
+// anything indexed changes, including author names that are not part of the article row.
+func (d *ArticleDocument) ContentChecksum() string {
+	content := *d
+	content.Checksum = ""
+	// Strings, string slices and times always marshal
+	b, _ := json.Marshal(content)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// SavedSearchFieldQuery is the percolator field of SavedSearchDocument.
+const SavedSearchFieldQuery = "query"
+
+// SavedSearchDocument is a saved search as stored in the percolator index, keyed by the saved search ID.
+// Percolating an ArticleDocument returns the saved searches whose query matches it.
+type SavedSearchDocument struct {
+	Query Query
+}
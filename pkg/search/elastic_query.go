@@ -0,0 +1,161 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// elasticQuery translates a query into the Elasticsearch query DSL.
+func elasticQuery(query Query) elastic.Query {
+	switch q := query.(type) {
+	case *BoolQuery:
+		boolQuery := elastic.NewBoolQuery()
+		for _, clause := range q.Must {
+			boolQuery.Must(elasticQuery(clause))
+		}
+		for _, clause := range q.Should {
+			boolQuery.Should(elasticQuery(clause))
+		}
+		for _, clause := range q.MustNot {
+			boolQuery.MustNot(elasticQuery(clause))
+		}
+		for _, clause := range q.Filter {
+			boolQuery.Filter(elasticQuery(clause))
+		}
+		return boolQuery
+	case *MatchQuery:
+		switch q.Mode {
+		case MatchPhrase:
+			return elastic.NewMultiMatchQuery(q.Text, q.Fields...).Type("phrase")
+		case MatchPrefix:
+			// Prefixes are matched on the search-as-you-type subfield, which indexes word n-grams
+			var fields []string
+			for _, field := range q.Fields {
+				name, _ := parseFieldBoost(field)
+				fields = append(fields, SuggestFields(name+".suggest")...)
+			}
+			return elastic.NewMultiMatchQuery(q.Text, fields...).Type("bool_prefix")
+		default:
+			match := elastic.NewMultiMatchQuery(q.Text, q.Fields...)
+			if q.Fuzzy {
+				match.Fuzziness("AUTO")
+			}
+			return match
+		}
+	case *TermsQuery:
+		values := make([]interface{}, len(q.Values))
+		for i, v := range q.Values {
+			values[i] = v
+		}
+		terms := elastic.NewTermsQuery(q.Field, values...)
+		if q.Boost > 0 {
+			terms.Boost(q.Boost)
+		}
+		return terms
+	case *DateRangeQuery:
+		dates := elastic.NewRangeQuery(q.Field)
+		if !q.From.IsZero() {
+			dates.Gte(q.From)
+		}
+		if !q.To.IsZero() {
+			dates.Lt(q.To)
+		}
+		return dates
+	case *IDsQuery:
+		return elastic.NewIdsQuery().Ids(q.IDs...)
+	case *MoreLikeThisQuery:
+		mlt := elastic.NewMoreLikeThisQuery().Field(q.Fields...).LikeText(q.LikeText)
+		if q.MinTermFreq > 0 {
+			mlt.MinTermFreq(q.MinTermFreq)
+		}
+		if q.MaxQueryTerms > 0 {
+			mlt.MaxQueryTerms(q.MaxQueryTerms)
+		}
+		return mlt
+	default:
+		return elastic.NewMatchAllQuery()
+	}
+}
+
+// Names of the sub-aggregations that terms aggregations are nested in.
+const (
+	globalAggregation = "global"
+	filterAggregation = "filter"
+)
+
+// elasticAggregation translates an aggregation into the Elasticsearch aggregation DSL.
+// A filtered or global terms aggregation is nested in a filter or global aggregation.
+func elasticAggregation(agg Aggregation) elastic.Aggregation {
+	switch a := agg.(type) {
+	case *TermsAggregation:
+		var terms elastic.Aggregation = elastic.NewTermsAggregation().Field(a.Field).Size(a.Size)
+		if a.Filter != nil {
+			terms = elastic.NewFilterAggregation().Filter(elasticQuery(a.Filter)).SubAggregation(filterAggregation, terms)
+		}
+		if a.Global {
+			terms = elastic.NewGlobalAggregation().SubAggregation(globalAggregation, terms)
+		}
+		return terms
+	case *DateHistogramAggregation:
+		return elastic.NewDateHistogramAggregation().
+			Field(a.Field).
+			CalendarInterval(a.Interval).
+			Format("yyyy-MM-dd").
+			MinDocCount(1)
+	default:
+		return nil
+	}
+}
+
+// readAggregation reads the buckets of an aggregation built by elasticAggregation.
+func readAggregation(aggs elastic.Aggregations, name string, agg Aggregation) []Bucket {
+	buckets := []Bucket{}
+	switch a := agg.(type) {
+	case *TermsAggregation:
+		// Unwrap the aggregations that elasticAggregation nested the terms in, outermost first
+		termsName := name
+		if a.Global {
+			global, ok := aggs.Global(termsName)
+			if !ok {
+				return buckets
+			}
+			aggs, termsName = global.Aggregations, globalAggregation
+		}
+		if a.Filter != nil {
+			filter, ok := aggs.Filter(termsName)
+			if !ok {
+				return buckets
+			}
+			aggs, termsName = filter.Aggregations, filterAggregation
+		}
+		if terms, ok := aggs.Terms(termsName); ok {
+			for _, bucket := range terms.Buckets {
+				buckets = append(buckets, Bucket{Key: fmt.Sprint(bucket.Key), Count: bucket.DocCount})
+			}
+		}
+	case *DateHistogramAggregation:
+		if dates, ok := aggs.DateHistogram(name); ok {
+			for _, bucket := range dates.Buckets {
+				if bucket.KeyAsString != nil {
+					buckets = append(buckets, Bucket{Key: *bucket.KeyAsString, Count: bucket.DocCount})
+				}
+			}
+		}
+	}
+	return buckets
+}
+
+// elasticDocument returns the body Elasticsearch stores for a document.
+// A saved search is stored as the Elasticsearch translation of its query.
+func elasticDocument(doc interface{}) (interface{}, error) {
+	saved, ok := doc.(*SavedSearchDocument)
+	if !ok {
+		return doc, nil
+	}
+	source, err := elasticQuery(saved.Query).Source()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build saved search query: %w", err)
+	}
+	return map[string]interface{}{SavedSearchFieldQuery: source}, nil
+}
@@ -0,0 +1,348 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/sirupsen/logrus"
+)
+
+// ArticleMappingVersion is the version of ArticleMapping. Bump it whenever the mapping
+// changes in a way that existing indices cannot be updated in place.
+const ArticleMappingVersion = 7
+
+// Analysis names declared in the settings of ArticleMapping.
+const (
+	articleAnalyzer       = "indonesian_text"
+	articleSearchAnalyzer = "indonesian_search"
+	articleSynonymFilter  = "article_synonyms"
+)
+
+// ArticleSynonymsPath is the search synonyms file of the articles index, relative to the config
+// directory of every Elasticsearch node. Editors maintain it there, one Solr-format rule per line,
+// and apply their changes with ReloadSearchAnalyzers.
+const ArticleSynonymsPath = "analysis/article_synonyms.txt"
+
+// articleSettings are the index settings of ArticleMapping, shared with SavedSearchMapping.
+// Title and body are analyzed as Indonesian: lowercased, without stopwords and stemmed
+// (e.g. "membaca" is indexed as "baca"). Synonyms are only expanded in queries, by an
+// updateable filter that reloads its file without closing the index.
+var articleSettings = fmt.Sprintf(`{
+    "number_of_shards": 1,
+    "number_of_replicas": 0,
+    "analysis": {
+      "filter": {
+        "indonesian_stop": { "type": "stop", "stopwords": "_indonesian_" },
+        "indonesian_stemmer": { "type": "stemmer", "language": "indonesian" },
+        "%[4]s": { "type": "synonym_graph", "synonyms_path": "%[3]s", "updateable": true }
+      },
+      "analyzer": {
+        "%[1]s": {
+          "tokenizer": "standard",
+          "filter": ["lowercase", "indonesian_stop", "indonesian_stemmer"]
+        },
+        "%[2]s": {
+          "tokenizer": "standard",
+          "filter": ["lowercase", "%[4]s", "indonesian_stop", "indonesian_stemmer"]
+        }
+      }
+    }
+  }`, articleAnalyzer, articleSearchAnalyzer, ArticleSynonymsPath, articleSynonymFilter)
+
+// articleProperties are the fields of ArticleDocument as declared in ArticleMapping.
+var articleProperties = fmt.Sprintf(`
+      "id": { "type": "keyword" },
+      "title": {
+        "type": "text",
+        "analyzer": "%[1]s",
+        "search_analyzer": "%[2]s",
+        "fields": { "suggest": { "type": "search_as_you_type" } }
+      },
+      "body": { "type": "text", "analyzer": "%[1]s", "search_analyzer": "%[2]s" },
+      "author": {
+        "type": "keyword",
+        "fields": { "suggest": { "type": "search_as_you_type" } }
+      },
+      "author_id": { "type": "keyword" },
+      "author_key": { "type": "keyword" },
+      "created_at": { "type": "date" },
+      "updated_at": { "type": "date" },
+      "checksum": { "type": "keyword", "index": false }`, articleAnalyzer, articleSearchAnalyzer)
+
+// ArticleMapping defines the settings and mapping of the articles index.
+// This helps Elasticsearch understand the data types and how to index them.
+var ArticleMapping = fmt.Sprintf(`
+{
+  "settings": %s,
+  "mappings": {
+    "_meta": %s,
+    "dynamic": "strict",
+    "properties": {%s
+    }
+  }
+}
+`, articleSettings, articleMeta(), articleProperties)
+
+// SavedSearchMapping defines the percolator index of saved searches. Articles percolated against it
+// are analyzed with its mapping, so it declares the article fields next to the stored queries.
+var SavedSearchMapping = fmt.Sprintf(`
+{
+  "settings": %s,
+  "mappings": {
+    "_meta": %s,
+    "dynamic": "strict",
+    "properties": {%s,
+      "%s": { "type": "percolator" }
+    }
+  }
+}
+`, articleSettings, articleMeta(), articleProperties, SavedSearchFieldQuery)
+
+// legacyArticleScript copies the documents of an older index into the current shape.
+// Fields that are not part of ArticleDocument are dropped so the strict mapping accepts them.
+const legacyArticleScript = `
+if (ctx._source.containsKey('published_at') && !ctx._source.containsKey('created_at')) {
+  ctx._source.created_at = ctx._source.published_at;
+}
+ctx._source.keySet().retainAll(params.fields);
+`
+
+// VersionedIndexName returns the concrete index behind the given alias for a mapping version.
+func VersionedIndexName(alias string, version int) string {
+	return fmt.Sprintf("%s_v%d", alias, version)
+}
+
+// EnsureArticleIndex makes ArticleIndexName an alias of the index for the current
+// ArticleMappingVersion, creating it when missing.
+// An index left by an older version, including the unversioned index that used to be
+// named ArticleIndexName itself, is copied into the new index before the alias is moved, and
+// it reports true. Copied documents lack the fields added since, until they are indexed again.
+func EnsureArticleIndex(ctx context.Context, client *elastic.Client) (bool, error) {
+	target := VersionedIndexName(ArticleIndexName, ArticleMappingVersion)
+
+	current, version, err := resolveIndex(ctx, client, ArticleIndexName)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case current == "":
+		if err := createIndex(ctx, client, target, ArticleMapping); err != nil {
+			return false, err
+		}
+		if _, err := switchAlias(ctx, client, ArticleIndexName, target); err != nil {
+			return false, err
+		}
+		logrus.Infof("Elasticsearch index '%s' created behind alias '%s'", target, ArticleIndexName)
+		return false, nil
+	case version == ArticleMappingVersion:
+		logrus.Infof("Elasticsearch index '%s' is up to date", current)
+		return false, nil
+	case version > ArticleMappingVersion:
+		// Rolled back binary; leave the newer index alone rather than downgrade it
+		logrus.Warnf("Elasticsearch index '%s' is newer than mapping version %d, leaving it as is", current, ArticleMappingVersion)
+		return false, nil
+	}
+
+	logrus.Infof("Migrating Elasticsearch index '%s' (version %d) to '%s'", current, version, target)
+	exists, err := client.IndexExists(target).Do(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check Elasticsearch index existence: %w", err)
+	}
+	if !exists {
+		if err := createIndex(ctx, client, target, ArticleMapping); err != nil {
+			return false, err
+		}
+	}
+
+	fields := []string{
+		ArticleFieldID, ArticleFieldTitle, ArticleFieldBody, ArticleFieldAuthor,
+		ArticleFieldAuthorID, ArticleFieldAuthorKey, ArticleFieldCreatedAt, ArticleFieldUpdatedAt, ArticleFieldChecksum,
+	}
+	copied, err := client.Reindex().
+		SourceIndex(current).
+		DestinationIndex(target).
+		Script(elastic.NewScriptInline(legacyArticleScript).Lang("painless").Param("fields", fields)).
+		Refresh("true").
+		Do(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to copy Elasticsearch index '%s': %w", current, err)
+	}
+	if len(copied.Failures) > 0 {
+		return false, fmt.Errorf("failed to copy %d documents from Elasticsearch index '%s'", len(copied.Failures), current)
+	}
+
+	if _, err := switchAlias(ctx, client, ArticleIndexName, target); err != nil {
+		return false, err
+	}
+
+	logrus.Infof("Elasticsearch index '%s' migrated to '%s' (%d documents)", current, target, copied.Created)
+	return true, nil
+}
+
+// EnsureSavedSearchIndex makes SavedSearchIndexName an alias of a percolator index built with the
+// current ArticleMappingVersion. Queries are parsed with the analysis of the index they are stored
+// in, so an outdated index is replaced rather than updated: register fills the new index from the
+// saved searches of record before the alias is moved, then the old index is deleted.
+func EnsureSavedSearchIndex(ctx context.Context, client *elastic.Client, register func(ctx context.Context, index string) error) error {
+	current, version, err := resolveIndex(ctx, client, SavedSearchIndexName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case current != "" && version == ArticleMappingVersion:
+		logrus.Infof("Elasticsearch index '%s' is up to date", current)
+		return nil
+	case version > ArticleMappingVersion:
+		logrus.Warnf("Elasticsearch index '%s' is newer than mapping version %d, leaving it as is", current, ArticleMappingVersion)
+		return nil
+	}
+
+	target := VersionedIndexName(SavedSearchIndexName, ArticleMappingVersion)
+	exists, err := client.IndexExists(target).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check Elasticsearch index existence: %w", err)
+	}
+	if exists {
+		target = fmt.Sprintf("%s_%d", target, time.Now().Unix())
+	}
+
+	if err := createIndex(ctx, client, target, SavedSearchMapping); err != nil {
+		return err
+	}
+	if err := register(ctx, target); err != nil {
+		return fmt.Errorf("failed to register saved searches in '%s': %w", target, err)
+	}
+	if _, err := client.Refresh(target).Do(ctx); err != nil {
+		return fmt.Errorf("failed to refresh Elasticsearch index '%s': %w", target, err)
+	}
+
+	previous, err := switchAlias(ctx, client, SavedSearchIndexName, target)
+	if err != nil {
+		return err
+	}
+	// The saved searches are kept in PostgreSQL, so the old index holds nothing worth keeping
+	for _, index := range previous {
+		if _, err := client.DeleteIndex(index).Do(ctx); err != nil {
+			logrus.WithError(err).Warnf("Failed to delete previous Elasticsearch index '%s'", index)
+		}
+	}
+
+	logrus.Infof("Elasticsearch index '%s' created behind alias '%s'", target, SavedSearchIndexName)
+	return nil
+}
+
+// resolveIndex returns the concrete index behind name and its mapping version.
+// A concrete index named like the alias predates versioning and counts as version 1.
+// It returns an empty index name when nothing exists yet.
+func resolveIndex(ctx context.Context, client *elastic.Client, name string) (string, int, error) {
+	exists, err := client.IndexExists(name).Do(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to check Elasticsearch index existence: %w", err)
+	}
+	if !exists {
+		return "", 0, nil
+	}
+
+	aliases, err := client.Aliases().Index(name).Do(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get Elasticsearch aliases: %w", err)
+	}
+	indices := aliases.IndicesByAlias(name)
+	switch len(indices) {
+	case 0:
+		return name, 1, nil
+	case 1:
+		return indices[0], parseIndexVersion(name, indices[0]), nil
+	default:
+		return "", 0, fmt.Errorf("alias '%s' points at %d indices, expected one", name, len(indices))
+	}
+}
+
+// switchAlias points alias at index in a single atomic request and returns the indices
+// it pointed at before. The unversioned index that predates aliases has the alias's
+// name, so it is removed in the same request; older versioned indices are kept.
+func switchAlias(ctx context.Context, client *elastic.Client, alias, index string) ([]string, error) {
+	current, _, err := resolveIndex(ctx, client, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous []string
+	aliases := client.Alias().Action(elastic.NewAliasAddAction(alias).Index(index))
+	switch current {
+	case "", index:
+	case alias:
+		aliases.Action(elastic.NewAliasRemoveIndexAction(current))
+	default:
+		aliases.Action(elastic.NewAliasRemoveAction(alias).Index(current))
+		previous = append(previous, current)
+	}
+
+	if _, err := aliases.Do(ctx); err != nil {
+		return nil, fmt.Errorf("failed to switch Elasticsearch alias '%s' to '%s': %w", alias, index, err)
+	}
+	return previous, nil
+}
+
+// parseIndexVersion extracts the version from an index named by VersionedIndexName,
+// optionally followed by a build suffix (e.g. articles_v2_1700000000).
+func parseIndexVersion(alias, index string) int {
+	suffix := strings.TrimPrefix(index, alias+"_v")
+	version, err := strconv.Atoi(strings.SplitN(suffix, "_", 2)[0])
+	if err != nil {
+		return 1
+	}
+	return version
+}
+
+// createIndex creates an index with the given settings and mapping.
+func createIndex(ctx context.Context, client *elastic.Client, name, body string) error {
+	created, err := client.CreateIndex(name).BodyString(body).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Elasticsearch index: %w", err)
+	}
+	if !created.Acknowledged {
+		return fmt.Errorf("failed to acknowledge Elasticsearch index creation")
+	}
+	return nil
+}
+
+// ReloadSearchAnalyzers makes the articles and saved searches indices read ArticleSynonymsPath again,
+// applying edits to the synonyms without closing or rebuilding them. Reloading is idempotent, so
+// several instances may do it at once.
+func ReloadSearchAnalyzers(ctx context.Context, client *elastic.Client) error {
+	res, err := client.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: "POST",
+		Path:   fmt.Sprintf("/%s,%s/_reload_search_analyzers", ArticleIndexName, SavedSearchIndexName),
+		Params: url.Values{"ignore_unavailable": []string{"true"}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reload Elasticsearch search analyzers: %w", err)
+	}
+
+	var reloaded struct {
+		Shards struct {
+			Failed int `json:"failed"`
+		} `json:"_shards"`
+	}
+	if err := json.Unmarshal(res.Body, &reloaded); err != nil {
+		return fmt.Errorf("failed to read Elasticsearch reload response: %w", err)
+	}
+	if reloaded.Shards.Failed > 0 {
+		return fmt.Errorf("failed to reload search analyzers on %d Elasticsearch shards", reloaded.Shards.Failed)
+	}
+	return nil
+}
+
+// articleMeta returns the _meta object of the articles mapping.
+func articleMeta() string {
+	return fmt.Sprintf(`{ "version": %d }`, ArticleMappingVersion)
+}
@@ -0,0 +1,122 @@
+package search_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"kumparan-test/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleMapping_MatchesArticleDocument(t *testing.T) {
+	var mapping struct {
+		Mappings struct {
+			Meta struct {
+				Version int `json:"version"`
+			} `json:"_meta"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"mappings"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(search.ArticleMapping), &mapping))
+
+	var fields []string
+	docType := reflect.TypeOf(search.ArticleDocument{})
+	for i := 0; i < docType.NumField(); i++ {
+		fields = append(fields, strings.Split(docType.Field(i).Tag.Get("json"), ",")[0])
+	}
+
+	var properties []string
+	for name := range mapping.Mappings.Properties {
+		properties = append(properties, name)
+	}
+
+	assert.ElementsMatch(t, fields, properties)
+	assert.Equal(t, search.ArticleMappingVersion, mapping.Mappings.Meta.Version)
+}
+
+func TestSavedSearchMapping_PercolatesArticles(t *testing.T) {
+	var articles, savedSearches struct {
+		Settings json.RawMessage `json:"settings"`
+		Mappings struct {
+			Properties map[string]struct {
+				Type string `json:"type"`
+			} `json:"properties"`
+		} `json:"mappings"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(search.ArticleMapping), &articles))
+	require.NoError(t, json.Unmarshal([]byte(search.SavedSearchMapping), &savedSearches))
+
+	// Percolated articles are analyzed like indexed ones
+	assert.JSONEq(t, string(articles.Settings), string(savedSearches.Settings))
+	for name, property := range articles.Mappings.Properties {
+		assert.Equal(t, property, savedSearches.Mappings.Properties[name], name)
+	}
+	assert.Equal(t, "percolator", savedSearches.Mappings.Properties[search.SavedSearchFieldQuery].Type)
+}
+
+func TestVersionedIndexName(t *testing.T) {
+	assert.Equal(t, "articles_v2", search.VersionedIndexName(search.ArticleIndexName, 2))
+}
+
+func TestArticleMapping_IndonesianAnalysisWithSynonyms(t *testing.T) {
+	var mapping struct {
+		Settings struct {
+			Analysis struct {
+				Filter map[string]struct {
+					Type         string `json:"type"`
+					SynonymsPath string `json:"synonyms_path"`
+					Updateable   bool   `json:"updateable"`
+				} `json:"filter"`
+				Analyzer map[string]struct {
+					Filter []string `json:"filter"`
+				} `json:"analyzer"`
+			} `json:"analysis"`
+		} `json:"settings"`
+		Mappings struct {
+			Properties map[string]struct {
+				Analyzer       string `json:"analyzer"`
+				SearchAnalyzer string `json:"search_analyzer"`
+			} `json:"properties"`
+		} `json:"mappings"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(search.ArticleMapping), &mapping))
+
+	for _, field := range []string{search.ArticleFieldTitle, search.ArticleFieldBody} {
+		property := mapping.Mappings.Properties[field]
+		require.Contains(t, mapping.Settings.Analysis.Analyzer, property.Analyzer, field)
+		require.Contains(t, mapping.Settings.Analysis.Analyzer, property.SearchAnalyzer, field)
+		assert.Contains(t, mapping.Settings.Analysis.Analyzer[property.Analyzer].Filter, "indonesian_stemmer", field)
+		// Updateable filters are only allowed in search analyzers
+		assert.NotContains(t, mapping.Settings.Analysis.Analyzer[property.Analyzer].Filter, "article_synonyms", field)
+		assert.Contains(t, mapping.Settings.Analysis.Analyzer[property.SearchAnalyzer].Filter, "article_synonyms", field)
+	}
+
+	synonyms := mapping.Settings.Analysis.Filter["article_synonyms"]
+	assert.Equal(t, "synonym_graph", synonyms.Type)
+	assert.Equal(t, search.ArticleSynonymsPath, synonyms.SynonymsPath)
+	assert.True(t, synonyms.Updateable)
+}
+
+func TestArticleSynonymsFile_IsShippedForElasticsearch(t *testing.T) {
+	// docker-compose mounts the elasticsearch directory into the config directory of the node
+	content, err := os.ReadFile(filepath.Join("..", "..", "elasticsearch", search.ArticleSynonymsPath))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "\npemilu, pemilihan umum\n")
+}
+
+func TestArticleDocument_ContentChecksum(t *testing.T) {
+	doc := &search.ArticleDocument{ID: "a1", Title: "Banjir", Authors: []string{"Bara"}, AuthorIDs: []string{"auth-1"}}
+	sum := doc.ContentChecksum()
+
+	doc.Checksum = sum
+	assert.Equal(t, sum, doc.ContentChecksum(), "the stored checksum is not part of the content")
+
+	doc.Authors = []string{"Bara Ali"}
+	assert.NotEqual(t, sum, doc.ContentChecksum())
+}
@@ -0,0 +1,1067 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// memoryTextFields are analyzed into words. Other string fields are keywords, matched exactly by
+// TermsQuery and aggregated as they are, though their words can still be matched by MatchQuery.
+var memoryTextFields = map[string]bool{ArticleFieldTitle: true, ArticleFieldBody: true}
+
+// memorySearchService is a SearchService that keeps its indices in process memory, for local
+// development and tests without Elasticsearch. Every index is an inverted index of the words of
+// its documents. Text is only lowercased and split into words, without the stopwords, stemming
+// and synonyms of ArticleMapping, and scores are a plain TF-IDF. Results differ from Elasticsearch
+// in ranking and also in which documents match: a search for a stemmed form or a synonym, e.g.
+// "membaca" for "baca", finds nothing here. Nothing survives a restart.
+type memorySearchService struct {
+	mu      sync.RWMutex
+	indices map[string]*memoryIndex
+	aliases map[string]string // Alias name to index name
+}
+
+// NewMemorySearchService creates an empty in-memory search backend.
+func NewMemorySearchService() SearchService {
+	return &memorySearchService{
+		indices: map[string]*memoryIndex{},
+		aliases: map[string]string{},
+	}
+}
+
+// IndexDocument adds or replaces a document, creating the index if it does not exist.
+func (s *memorySearchService) IndexDocument(ctx context.Context, indexName string, id string, doc interface{}) error {
+	document, err := newMemoryDocument(doc)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writableIndex(indexName).put(id, document)
+	return nil
+}
+
+// UpdateDocument merges the given fields into an existing document, creating it if it does not exist.
+func (s *memorySearchService) UpdateDocument(ctx context.Context, indexName string, id string, doc interface{}) error {
+	update, err := newMemoryDocument(doc)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index := s.writableIndex(indexName)
+	if existing, ok := index.docs[id]; ok && update.source != nil {
+		merged := make(map[string]interface{}, len(existing.source))
+		for field, value := range existing.source {
+			merged[field] = value
+		}
+		for field, value := range update.source {
+			merged[field] = value
+		}
+		update.source = merged
+	}
+	index.put(id, update)
+	return nil
+}
+
+// DeleteDocument removes a document. Deleting a document that is not indexed is not an error.
+func (s *memorySearchService) DeleteDocument(ctx context.Context, indexName string, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index, ok := s.indices[s.resolve(indexName)]; ok {
+		index.remove(id)
+	}
+	return nil
+}
+
+// SearchDocuments runs a query against an index.
+func (s *memorySearchService) SearchDocuments(ctx context.Context, indexName string, query Query, opts SearchOptions) (*SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	index, ok := s.indices[s.resolve(indexName)]
+	if !ok {
+		return nil, fmt.Errorf("failed to perform search: no such index [%s]", indexName)
+	}
+	return index.search(query, opts)
+}
+
+// Percolate returns the IDs of the stored queries in a percolator index that match doc.
+func (s *memorySearchService) Percolate(ctx context.Context, indexName string, doc interface{}) ([]string, error) {
+	document, err := newMemoryDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	index, ok := s.indices[s.resolve(indexName)]
+	if !ok {
+		return nil, fmt.Errorf("failed to percolate document: no such index [%s]", indexName)
+	}
+
+	// The document is matched on its own, as if it were the only one indexed
+	single := newMemoryIndex()
+	single.put("", document)
+
+	ids := []string{}
+	for id, stored := range index.docs {
+		if stored.query == nil {
+			continue
+		}
+		if _, ok := single.eval(stored.query)[""]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > maxPercolateMatches {
+		ids = ids[:maxPercolateMatches]
+	}
+	return ids, nil
+}
+
+// BulkIndexDocuments adds or replaces many documents, keyed by ID.
+func (s *memorySearchService) BulkIndexDocuments(ctx context.Context, indexName string, docs map[string]interface{}) error {
+	documents := make(map[string]*memoryDocument, len(docs))
+	for id, doc := range docs {
+		document, err := newMemoryDocument(doc)
+		if err != nil {
+			return err
+		}
+		documents[id] = document
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index := s.writableIndex(indexName)
+	for id, document := range documents {
+		index.put(id, document)
+	}
+	return nil
+}
+
+// IndexExists reports whether an index or alias with the given name exists.
+func (s *memorySearchService) IndexExists(ctx context.Context, indexName string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.indices[s.resolve(indexName)]
+	return ok, nil
+}
+
+// CreateIndex creates an empty index. The settings and mapping in body are not used.
+func (s *memorySearchService) CreateIndex(ctx context.Context, indexName string, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.indices[s.resolve(indexName)]; ok {
+		return fmt.Errorf("failed to create index: index [%s] already exists", indexName)
+	}
+	s.indices[indexName] = newMemoryIndex()
+	return nil
+}
+
+// DeleteIndex removes an index, all of its documents and the aliases pointing at it.
+func (s *memorySearchService) DeleteIndex(ctx context.Context, indexName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := s.resolve(indexName)
+	if _, ok := s.indices[name]; !ok {
+		return fmt.Errorf("failed to delete index: no such index [%s]", indexName)
+	}
+	delete(s.indices, name)
+	for alias, target := range s.aliases {
+		if target == name {
+			delete(s.aliases, alias)
+		}
+	}
+	return nil
+}
+
+// SwitchAlias points the alias at the given index and returns the index it pointed at before.
+// An index named like the alias is removed, as it would otherwise shadow it.
+func (s *memorySearchService) SwitchAlias(ctx context.Context, alias string, indexName string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.indices[indexName]; !ok {
+		return nil, fmt.Errorf("failed to switch alias '%s': no such index [%s]", alias, indexName)
+	}
+
+	var previous []string
+	if current, ok := s.aliases[alias]; ok && current != indexName {
+		previous = append(previous, current)
+	}
+	delete(s.indices, alias)
+	s.aliases[alias] = indexName
+	return previous, nil
+}
+
+// Close does nothing, there is no connection to release.
+func (s *memorySearchService) Close() {}
+
+// resolve returns the index an alias points at, or the name itself when it is not an alias.
+func (s *memorySearchService) resolve(name string) string {
+	if index, ok := s.aliases[name]; ok {
+		return index
+	}
+	return name
+}
+
+// writableIndex returns the index behind name, creating it when missing like Elasticsearch does on writes.
+func (s *memorySearchService) writableIndex(name string) *memoryIndex {
+	name = s.resolve(name)
+	index, ok := s.indices[name]
+	if !ok {
+		index = newMemoryIndex()
+		s.indices[name] = index
+	}
+	return index
+}
+
+// memoryDocument is an indexed document: its JSON fields, or the query of a percolator document.
+type memoryDocument struct {
+	source map[string]interface{}
+	query  Query
+}
+
+// newMemoryDocument reads a document the way Elasticsearch would receive it, as JSON.
+func newMemoryDocument(doc interface{}) (*memoryDocument, error) {
+	switch d := doc.(type) {
+	case *SavedSearchDocument:
+		return &memoryDocument{query: d.Query}, nil
+	case SavedSearchDocument:
+		return &memoryDocument{query: d.Query}, nil
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	var source map[string]interface{}
+	if err := json.Unmarshal(raw, &source); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	return &memoryDocument{source: source}, nil
+}
+
+// memoryIndex holds documents together with the inverted index of their words.
+type memoryIndex struct {
+	docs     map[string]*memoryDocument
+	postings map[string]map[string]map[string][]int // Field, word, document ID: positions of the word
+	keywords map[string]map[string]map[string]bool  // Field, exact value: IDs of the documents having it
+}
+
+func newMemoryIndex() *memoryIndex {
+	return &memoryIndex{
+		docs:     map[string]*memoryDocument{},
+		postings: map[string]map[string]map[string][]int{},
+		keywords: map[string]map[string]map[string]bool{},
+	}
+}
+
+// put adds or replaces a document.
+func (ix *memoryIndex) put(id string, doc *memoryDocument) {
+	ix.remove(id)
+	ix.docs[id] = doc
+
+	for field, value := range doc.source {
+		for i, text := range fieldValues(value) {
+			if !memoryTextFields[field] {
+				if ix.keywords[field] == nil {
+					ix.keywords[field] = map[string]map[string]bool{}
+				}
+				if ix.keywords[field][text] == nil {
+					ix.keywords[field][text] = map[string]bool{}
+				}
+				ix.keywords[field][text][id] = true
+			}
+
+			if ix.postings[field] == nil {
+				ix.postings[field] = map[string]map[string][]int{}
+			}
+			for position, token := range analyze(text) {
+				if ix.postings[field][token.term] == nil {
+					ix.postings[field][token.term] = map[string][]int{}
+				}
+				ix.postings[field][token.term][id] = append(ix.postings[field][token.term][id], i*memoryPositionGap+position)
+			}
+		}
+	}
+}
+
+// remove deletes a document and its words from the index.
+func (ix *memoryIndex) remove(id string) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	delete(ix.docs, id)
+
+	for field, value := range doc.source {
+		for _, text := range fieldValues(value) {
+			if values := ix.keywords[field]; values != nil {
+				delete(values[text], id)
+				if len(values[text]) == 0 {
+					delete(values, text)
+				}
+			}
+			for _, token := range analyze(text) {
+				if docs := ix.postings[field][token.term]; docs != nil {
+					delete(docs, id)
+					if len(docs) == 0 {
+						delete(ix.postings[field], token.term)
+					}
+				}
+			}
+		}
+	}
+}
+
+// memoryPositionGap separates the word positions of the values of an array field, like the position_increment_gap
+// of Elasticsearch, so that a phrase cannot match across two values.
+const memoryPositionGap = 100
+
+// fieldValues returns the strings of a document field, which holds a single value or an array of values.
+func fieldValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := []string{}
+		for _, item := range v {
+			if text, ok := item.(string); ok {
+				values = append(values, text)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// memoryMatch is how well a document matches a query, with the words it matched per field for highlighting.
+type memoryMatch struct {
+	score float64
+	words map[string]map[string]bool
+}
+
+func (m *memoryMatch) addWord(field, word string) {
+	if m.words == nil {
+		m.words = map[string]map[string]bool{}
+	}
+	if m.words[field] == nil {
+		m.words[field] = map[string]bool{}
+	}
+	m.words[field][word] = true
+}
+
+// merge adds the score and words of another match of the same document.
+func (m *memoryMatch) merge(other *memoryMatch) {
+	m.score += other.score
+	for field, words := range other.words {
+		for word := range words {
+			m.addWord(field, word)
+		}
+	}
+}
+
+// eval returns the documents matching a query, keyed by ID.
+func (ix *memoryIndex) eval(query Query) map[string]*memoryMatch {
+	switch q := query.(type) {
+	case *BoolQuery:
+		return ix.evalBool(q)
+	case *MatchQuery:
+		return ix.evalMatch(q)
+	case *TermsQuery:
+		boost := q.Boost
+		if boost <= 0 {
+			boost = 1
+		}
+		matches := map[string]*memoryMatch{}
+		for _, value := range q.Values {
+			for id := range ix.keywords[q.Field][value] {
+				matches[id] = &memoryMatch{score: boost}
+			}
+		}
+		return matches
+	case *DateRangeQuery:
+		matches := map[string]*memoryMatch{}
+		for id, doc := range ix.docs {
+			date, ok := documentTime(doc.source[q.Field])
+			if ok && (q.From.IsZero() || !date.Before(q.From)) && (q.To.IsZero() || date.Before(q.To)) {
+				matches[id] = &memoryMatch{score: 1}
+			}
+		}
+		return matches
+	case *IDsQuery:
+		matches := map[string]*memoryMatch{}
+		for _, id := range q.IDs {
+			if _, ok := ix.docs[id]; ok {
+				matches[id] = &memoryMatch{score: 1}
+			}
+		}
+		return matches
+	case *MoreLikeThisQuery:
+		return ix.evalMoreLikeThis(q)
+	default:
+		return ix.all(1)
+	}
+}
+
+// all matches every document of the index with the same score.
+func (ix *memoryIndex) all(score float64) map[string]*memoryMatch {
+	matches := make(map[string]*memoryMatch, len(ix.docs))
+	for id, doc := range ix.docs {
+		if doc.query == nil {
+			matches[id] = &memoryMatch{score: score}
+		}
+	}
+	return matches
+}
+
+func (ix *memoryIndex) evalBool(q *BoolQuery) map[string]*memoryMatch {
+	var matches map[string]*memoryMatch
+	required := func(clause map[string]*memoryMatch, scored bool) {
+		if matches == nil {
+			matches = map[string]*memoryMatch{}
+			for id, match := range clause {
+				if !scored {
+					match.score = 0
+				}
+				matches[id] = match
+			}
+			return
+		}
+		for id, match := range matches {
+			other, ok := clause[id]
+			if !ok {
+				delete(matches, id)
+				continue
+			}
+			if scored {
+				match.merge(other)
+			}
+		}
+	}
+	for _, clause := range q.Must {
+		required(ix.eval(clause), true)
+	}
+	for _, clause := range q.Filter {
+		required(ix.eval(clause), false)
+	}
+
+	if matches == nil {
+		// Nothing is required, so a document has to match one of the should queries, if any
+		if len(q.Should) == 0 {
+			matches = ix.all(0)
+		} else {
+			matches = map[string]*memoryMatch{}
+			for _, clause := range q.Should {
+				for id, match := range ix.eval(clause) {
+					if existing, ok := matches[id]; ok {
+						existing.merge(match)
+					} else {
+						matches[id] = match
+					}
+				}
+			}
+		}
+	} else {
+		for _, clause := range q.Should {
+			for id, match := range ix.eval(clause) {
+				if existing, ok := matches[id]; ok {
+					existing.merge(match)
+				}
+			}
+		}
+	}
+
+	for _, clause := range q.MustNot {
+		for id := range ix.eval(clause) {
+			delete(matches, id)
+		}
+	}
+	return matches
+}
+
+// evalMatch scores each document by its best matching field.
+func (ix *memoryIndex) evalMatch(q *MatchQuery) map[string]*memoryMatch {
+	var words []string
+	for _, token := range analyze(q.Text) {
+		words = append(words, token.term)
+	}
+	matches := map[string]*memoryMatch{}
+	if len(words) == 0 {
+		return matches
+	}
+
+	for _, field := range q.Fields {
+		name, boost := parseFieldBoost(field)
+		var fieldMatches map[string]*memoryMatch
+		if q.Mode == MatchPhrase {
+			fieldMatches = ix.matchPhrase(name, words)
+		} else {
+			fieldMatches = ix.matchWords(name, words, q.Fuzzy, q.Mode == MatchPrefix)
+		}
+		for id, match := range fieldMatches {
+			match.score *= boost
+			best, ok := matches[id]
+			if !ok {
+				matches[id] = match
+				continue
+			}
+			// The score is the best field's, but every matching field is highlighted
+			score := math.Max(best.score, match.score)
+			best.merge(match)
+			best.score = score
+		}
+	}
+	return matches
+}
+
+// matchWords matches documents containing any of the words in field. With prefix set the last
+// word also matches the words starting with it; with fuzzy set words also match misspellings.
+func (ix *memoryIndex) matchWords(field string, words []string, fuzzy, prefix bool) map[string]*memoryMatch {
+	matches := map[string]*memoryMatch{}
+	for i, word := range words {
+		for term, weight := range ix.expand(field, word, fuzzy, prefix && i == len(words)-1) {
+			docs := ix.postings[field][term]
+			idf := ix.idf(len(docs))
+			for id, positions := range docs {
+				match, ok := matches[id]
+				if !ok {
+					match = &memoryMatch{}
+					matches[id] = match
+				}
+				match.score += weight * (1 + math.Log(float64(len(positions)))) * idf
+				match.addWord(field, term)
+			}
+		}
+	}
+	return matches
+}
+
+// expand returns the indexed words a query word matches in field, weighted by how closely.
+func (ix *memoryIndex) expand(field, word string, fuzzy, prefix bool) map[string]float64 {
+	terms := map[string]float64{}
+	if _, ok := ix.postings[field][word]; ok {
+		terms[word] = 1
+	}
+	if !fuzzy && !prefix {
+		return terms
+	}
+
+	maxEdits := fuzzyEdits(word)
+	for term := range ix.postings[field] {
+		if term == word {
+			continue
+		}
+		if prefix && strings.HasPrefix(term, word) {
+			terms[term] = 1
+		} else if fuzzy && maxEdits > 0 && editDistance(word, term) <= maxEdits {
+			// A misspelling counts less than the word itself
+			terms[term] = 0.5
+		}
+	}
+	return terms
+}
+
+// matchPhrase matches documents containing the words next to each other in field.
+func (ix *memoryIndex) matchPhrase(field string, words []string) map[string]*memoryMatch {
+	matches := map[string]*memoryMatch{}
+	first := ix.postings[field][words[0]]
+
+	for id, starts := range first {
+		found := false
+		for _, start := range starts {
+			found = true
+			for offset, word := range words[1:] {
+				if !containsInt(ix.postings[field][word][id], start+offset+1) {
+					found = false
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		match := &memoryMatch{}
+		for _, word := range words {
+			match.score += ix.idf(len(ix.postings[field][word]))
+			match.addWord(field, word)
+		}
+		matches[id] = match
+	}
+	return matches
+}
+
+// evalMoreLikeThis picks the most significant words of the text and matches documents containing
+// enough of them, like Elasticsearch's more_like_this with its default 30% minimum_should_match.
+func (ix *memoryIndex) evalMoreLikeThis(q *MoreLikeThisQuery) map[string]*memoryMatch {
+	minTermFreq, maxQueryTerms := q.MinTermFreq, q.MaxQueryTerms
+	if minTermFreq <= 0 {
+		minTermFreq = 2
+	}
+	if maxQueryTerms <= 0 {
+		maxQueryTerms = 25
+	}
+
+	freq := map[string]int{}
+	for _, token := range analyze(q.LikeText) {
+		freq[token.term]++
+	}
+	type candidate struct {
+		word   string
+		weight float64
+	}
+	var candidates []candidate
+	for word, n := range freq {
+		if n < minTermFreq {
+			continue
+		}
+		df := 0
+		for _, field := range q.Fields {
+			df = max(df, len(ix.postings[field][word]))
+		}
+		if df > 0 {
+			candidates = append(candidates, candidate{word, float64(n) * ix.idf(df)})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].weight != candidates[j].weight {
+			return candidates[i].weight > candidates[j].weight
+		}
+		return candidates[i].word < candidates[j].word
+	})
+	if len(candidates) > maxQueryTerms {
+		candidates = candidates[:maxQueryTerms]
+	}
+
+	matches := map[string]*memoryMatch{}
+	matched := map[string]int{}
+	for _, c := range candidates {
+		seen := map[string]bool{}
+		for _, field := range q.Fields {
+			for id, match := range ix.matchWords(field, []string{c.word}, false, false) {
+				if existing, ok := matches[id]; ok {
+					existing.merge(match)
+				} else {
+					matches[id] = match
+				}
+				if !seen[id] {
+					seen[id] = true
+					matched[id]++
+				}
+			}
+		}
+	}
+
+	minimum := max(1, len(candidates)*3/10)
+	for id := range matches {
+		if matched[id] < minimum {
+			delete(matches, id)
+		}
+	}
+	return matches
+}
+
+// idf weighs a word by how rare it is among the documents.
+func (ix *memoryIndex) idf(docFreq int) float64 {
+	return math.Log(1 + (float64(len(ix.docs))-float64(docFreq)+0.5)/(float64(docFreq)+0.5))
+}
+
+// memoryHit is a matching document with its position in the requested order.
+type memoryHit struct {
+	id    string
+	doc   *memoryDocument
+	match *memoryMatch
+	sort  []interface{}
+}
+
+// search runs a query and returns the requested page of hits.
+func (ix *memoryIndex) search(query Query, opts SearchOptions) (*SearchResult, error) {
+	matches := ix.eval(query)
+
+	sortFields := opts.Sort
+	if len(sortFields) == 0 {
+		sortFields = []SortField{{Field: ScoreField}}
+	}
+	hits := make([]*memoryHit, 0, len(matches))
+	for id, match := range matches {
+		hit := &memoryHit{id: id, doc: ix.docs[id], match: match}
+		for _, field := range sortFields {
+			hit.sort = append(hit.sort, hit.sortValue(field.Field))
+		}
+		hits = append(hits, hit)
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if c := compareSortValues(hits[i].sort, hits[j].sort, sortFields); c != 0 {
+			return c < 0
+		}
+		// Documents in the same position are returned in a stable order
+		return hits[i].id < hits[j].id
+	})
+
+	result := &SearchResult{Total: int64(len(hits)), Hits: []*Hit{}, Aggregations: map[string][]Bucket{}}
+	for name, agg := range opts.Aggregations {
+		result.Aggregations[name] = ix.aggregate(agg, matches)
+	}
+
+	page := hits
+	if len(opts.SearchAfter) > 0 {
+		page = page[sort.Search(len(page), func(i int) bool {
+			return compareSortValues(page[i].sort, opts.SearchAfter, sortFields) > 0
+		}):]
+	} else if opts.From > 0 {
+		page = page[min(opts.From, len(page)):]
+	}
+	page = page[:min(max(opts.Size, 0), len(page))]
+
+	scored := len(opts.Sort) == 0 || opts.TrackScores
+	for _, field := range opts.Sort {
+		scored = scored || field.Field == ScoreField
+	}
+	for _, hit := range page {
+		source, err := hit.sourceJSON(opts.Source)
+		if err != nil {
+			return nil, err
+		}
+		out := &Hit{ID: hit.id, Source: source}
+		if scored {
+			score := hit.match.score
+			out.Score = &score
+		}
+		if len(opts.Sort) > 0 {
+			out.Sort = hit.sort
+		}
+		if opts.Highlight != nil {
+			out.Highlight = hit.highlight(opts.Highlight)
+		}
+		result.Hits = append(result.Hits, out)
+	}
+	return result, nil
+}
+
+// sortValue returns the value a hit is sorted by for field: its score, a date as epoch
+// milliseconds like Elasticsearch, or the field value itself.
+func (h *memoryHit) sortValue(field string) interface{} {
+	if field == ScoreField {
+		return h.match.score
+	}
+	value := h.doc.source[field]
+	if date, ok := documentTime(value); ok && !memoryTextFields[field] && field != ArticleFieldID {
+		return date.UnixMilli()
+	}
+	return value
+}
+
+// sourceJSON returns the document fields, limited to fields when set.
+func (h *memoryHit) sourceJSON(fields []string) (json.RawMessage, error) {
+	if h.doc.source == nil {
+		return nil, nil
+	}
+	source := h.doc.source
+	if len(fields) > 0 {
+		source = map[string]interface{}{}
+		for _, field := range fields {
+			if value, ok := h.doc.source[field]; ok {
+				source[field] = value
+			}
+		}
+	}
+	raw, err := json.Marshal(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document %s: %w", h.id, err)
+	}
+	return raw, nil
+}
+
+// highlight returns fragments of the requested fields around the words the hit matched.
+func (h *memoryHit) highlight(opts *HighlightOptions) map[string][]string {
+	highlights := map[string][]string{}
+	for _, field := range opts.Fields {
+		text, _ := h.doc.source[field].(string)
+		words := h.match.words[field]
+		if text == "" || len(words) == 0 {
+			continue
+		}
+		if fragments := highlightFragments(text, words, opts); len(fragments) > 0 {
+			highlights[field] = fragments
+		}
+	}
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// highlightFragments cuts text into fragments of about opts.FragmentSize characters starting
+// shortly before a matched word, escapes them as HTML and wraps the matched words in the tags.
+func highlightFragments(text string, words map[string]bool, opts *HighlightOptions) []string {
+	size := opts.FragmentSize
+	if size <= 0 {
+		size = 100
+	}
+	var matched []token
+	for _, t := range analyze(text) {
+		if words[t.term] {
+			matched = append(matched, t)
+		}
+	}
+
+	var fragments []string
+	for i := 0; i < len(matched) && (opts.Fragments <= 0 || len(fragments) < opts.Fragments); {
+		// Start at a word boundary a little before the match, end at one after size characters
+		start := matched[i].start
+		if lead := start - size/4; lead <= 0 {
+			start = 0
+		} else if space := strings.IndexAny(text[lead:start], " \t\r\n"); space >= 0 {
+			start = lead + space + 1
+		}
+		end := len(text)
+		if start+size < len(text) {
+			end = start + size
+			if space := strings.IndexAny(text[end:], " \t\r\n"); space >= 0 {
+				end += space
+			} else {
+				end = len(text)
+			}
+		}
+
+		var b strings.Builder
+		pos := start
+		for ; i < len(matched) && matched[i].end <= end; i++ {
+			b.WriteString(html.EscapeString(text[pos:matched[i].start]))
+			b.WriteString(opts.PreTag)
+			b.WriteString(html.EscapeString(text[matched[i].start:matched[i].end]))
+			b.WriteString(opts.PostTag)
+			pos = matched[i].end
+		}
+		b.WriteString(html.EscapeString(text[pos:end]))
+		fragments = append(fragments, strings.TrimSpace(b.String()))
+
+		if pos == start {
+			// A single word longer than the fragment, skip it rather than loop
+			i++
+		}
+	}
+	return fragments
+}
+
+// aggregate computes the buckets of an aggregation over the matching documents.
+func (ix *memoryIndex) aggregate(agg Aggregation, matches map[string]*memoryMatch) []Bucket {
+	buckets := []Bucket{}
+	switch a := agg.(type) {
+	case *TermsAggregation:
+		docs := matches
+		if a.Global {
+			docs = ix.all(0)
+		}
+		if a.Filter != nil {
+			// The matches are shared with the hits and the other aggregations, so they are left as they are
+			filtered := ix.eval(a.Filter)
+			kept := make(map[string]*memoryMatch, len(filtered))
+			for id, match := range docs {
+				if _, ok := filtered[id]; ok {
+					kept[id] = match
+				}
+			}
+			docs = kept
+		}
+		counts := map[string]int64{}
+		for id := range docs {
+			for _, value := range fieldValues(ix.docs[id].source[a.Field]) {
+				counts[value]++
+			}
+		}
+		for key, count := range counts {
+			buckets = append(buckets, Bucket{Key: key, Count: count})
+		}
+		sort.Slice(buckets, func(i, j int) bool {
+			if buckets[i].Count != buckets[j].Count {
+				return buckets[i].Count > buckets[j].Count
+			}
+			return buckets[i].Key < buckets[j].Key
+		})
+		size := a.Size
+		if size <= 0 {
+			size = 10
+		}
+		if len(buckets) > size {
+			buckets = buckets[:size]
+		}
+	case *DateHistogramAggregation:
+		counts := map[string]int64{}
+		for id := range matches {
+			if date, ok := documentTime(ix.docs[id].source[a.Field]); ok {
+				counts[intervalStart(date, a.Interval).Format(time.DateOnly)]++
+			}
+		}
+		for key, count := range counts {
+			buckets = append(buckets, Bucket{Key: key, Count: count})
+		}
+		sort.Slice(buckets, func(i, j int) bool { return buckets[i].Key < buckets[j].Key })
+	}
+	return buckets
+}
+
+// intervalStart returns the first day, in UTC, of the calendar interval containing t.
+func intervalStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case "week":
+		// Weeks start on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// compareSortValues compares two sort positions field by field in the requested directions.
+func compareSortValues(a, b []interface{}, fields []SortField) int {
+	for i, field := range fields {
+		if i >= len(a) || i >= len(b) {
+			break
+		}
+		c := compareValues(a[i], b[i])
+		// Relevance and the default order are descending
+		if !field.Ascending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareValues orders numbers and strings, whatever their concrete types after a JSON round trip.
+func compareValues(a, b interface{}) int {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// documentTime reads a date field as encoded in JSON.
+func documentTime(value interface{}) (time.Time, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	return t, err == nil
+}
+
+// token is an analyzed word with its byte offsets in the text.
+type token struct {
+	term       string
+	start, end int
+}
+
+// analyze splits text into lowercase words of letters and digits.
+func analyze(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		wordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case wordRune && start < 0:
+			start = i
+		case !wordRune && start >= 0:
+			tokens = append(tokens, token{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// fuzzyEdits returns the typos tolerated in a word, like Elasticsearch's AUTO fuzziness.
+func fuzzyEdits(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance counts the insertions, deletions, substitutions and transpositions of adjacent
+// characters turning a into b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+package search_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"kumparan-test/pkg/search"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryArticles returns a memory backend with the given articles indexed under the articles alias.
+func memoryArticles(t *testing.T, docs ...*search.ArticleDocument) search.SearchService {
+	t.Helper()
+	ctx := context.Background()
+	svc := search.NewMemorySearchService()
+	require.NoError(t, svc.CreateIndex(ctx, "articles_v4", search.ArticleMapping))
+	_, err := svc.SwitchAlias(ctx, search.ArticleIndexName, "articles_v4")
+	require.NoError(t, err)
+	for _, doc := range docs {
+		require.NoError(t, svc.IndexDocument(ctx, search.ArticleIndexName, doc.ID, doc))
+	}
+	return svc
+}
+
+func hitIDs(result *search.SearchResult) []string {
+	ids := []string{}
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+var memoryDocs = []*search.ArticleDocument{
+	{ID: "a1", Title: "Banjir Jakarta", Body: "Curah hujan tinggi membuat banjir di Jakarta.", Authors: []string{"Bara"}, CreatedAt: time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)},
+	{ID: "a2", Title: "Macet di Bogor", Body: "Hujan deras di kota hujan membuat jalan macet.", Authors: []string{"Sari"}, CreatedAt: time.Date(2025, 1, 8, 8, 0, 0, 0, time.UTC)},
+	{ID: "a3", Title: "Pemilu Damai", Body: "Banjir tidak menghalangi warga memilih.", Authors: []string{"Bara Ali"}, CreatedAt: time.Date(2025, 2, 3, 8, 0, 0, 0, time.UTC)},
+}
+
+func TestMemorySearch_MatchesWordsAndBoostsTitles(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+
+	result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName,
+		&search.MatchQuery{Text: "banjir", Fields: []string{"title^3", "body"}}, search.SearchOptions{Size: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, []string{"a1", "a3"}, hitIDs(result))
+	require.NotNil(t, result.Hits[0].Score)
+	assert.Greater(t, *result.Hits[0].Score, *result.Hits[1].Score)
+
+	var doc search.ArticleDocument
+	require.NoError(t, json.Unmarshal(result.Hits[0].Source, &doc))
+	assert.Equal(t, "Banjir Jakarta", doc.Title)
+}
+
+func TestMemorySearch_FuzzyToleratesTypos(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+	query := &search.MatchQuery{Text: "banjr", Fields: []string{"title", "body"}}
+
+	exact, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName, query, search.SearchOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Empty(t, exact.Hits)
+
+	query.Fuzzy = true
+	fuzzy, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName, query, search.SearchOptions{Size: 10})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a1", "a3"}, hitIDs(fuzzy))
+}
+
+func TestMemorySearch_PhrasesExclusionsAndFilters(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+
+	query := &search.BoolQuery{
+		Must:    []search.Query{&search.MatchQuery{Text: "hujan", Fields: []string{"body"}}},
+		MustNot: []search.Query{&search.MatchQuery{Text: "kota hujan", Fields: []string{"body"}, Mode: search.MatchPhrase}},
+	}
+	result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName, query, search.SearchOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1"}, hitIDs(result))
+
+	query = &search.BoolQuery{
+		Must: []search.Query{&search.MatchQuery{Text: "banjir", Fields: []string{"title", "body"}}},
+		Filter: []search.Query{
+			&search.TermsQuery{Field: search.ArticleFieldAuthor, Values: []string{"Bara Ali"}},
+			&search.DateRangeQuery{Field: search.ArticleFieldCreatedAt, From: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	result, err = svc.SearchDocuments(context.Background(), search.ArticleIndexName, query, search.SearchOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a3"}, hitIDs(result))
+}
+
+func TestMemorySearch_SortsAndPagesWithSearchAfter(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+	opts := search.SearchOptions{
+		Size: 2,
+		Sort: []search.SortField{{Field: search.ArticleFieldCreatedAt}, {Field: search.ArticleFieldID}},
+	}
+
+	first, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName, &search.MatchAllQuery{}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a3", "a2"}, hitIDs(first))
+	assert.Nil(t, first.Hits[0].Score)
+
+	// The sort values of the last hit come back from a cursor as JSON numbers
+	raw, err := json.Marshal(first.Hits[1].Sort)
+	require.NoError(t, err)
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&opts.SearchAfter))
+
+	next, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName, &search.MatchAllQuery{}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1"}, hitIDs(next))
+	assert.Equal(t, int64(3), next.Total)
+}
+
+func TestMemorySearch_HighlightsMatchedWords(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+
+	result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName,
+		&search.MatchQuery{Text: "jakarta", Fields: []string{"title", "body"}},
+		search.SearchOptions{Size: 10, Highlight: &search.HighlightOptions{
+			Fields:       []string{"title", "body"},
+			FragmentSize: 150,
+			Fragments:    3,
+			PreTag:       "<em>",
+			PostTag:      "</em>",
+		}})
+
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, map[string][]string{
+		"title": {"Banjir <em>Jakarta</em>"},
+		"body":  {"Curah hujan tinggi membuat banjir di <em>Jakarta</em>."},
+	}, result.Hits[0].Highlight)
+}
+
+func TestMemorySearch_Aggregations(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+
+	result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName,
+		&search.MatchQuery{Text: "banjir", Fields: []string{"title", "body"}},
+		search.SearchOptions{Aggregations: map[string]search.Aggregation{
+			"authors": &search.TermsAggregation{Field: search.ArticleFieldAuthor, Size: 10},
+			"weeks":   &search.DateHistogramAggregation{Field: search.ArticleFieldCreatedAt, Interval: "week"},
+			"everyone": &search.TermsAggregation{
+				Field:  search.ArticleFieldAuthor,
+				Size:   10,
+				Filter: &search.MatchQuery{Text: "ba", Fields: []string{search.ArticleFieldAuthor}, Mode: search.MatchPrefix},
+				Global: true,
+			},
+		}})
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Hits)
+	assert.Equal(t, []search.Bucket{{Key: "Bara", Count: 1}, {Key: "Bara Ali", Count: 1}}, result.Aggregations["authors"])
+	assert.Equal(t, []search.Bucket{{Key: "2025-01-06", Count: 1}, {Key: "2025-02-03", Count: 1}}, result.Aggregations["weeks"])
+	assert.Equal(t, []search.Bucket{{Key: "Bara", Count: 1}, {Key: "Bara Ali", Count: 1}}, result.Aggregations["everyone"])
+}
+
+func TestMemorySearch_FilteredAggregationLeavesOtherAggregationsAlone(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+
+	// Aggregations run in no particular order, so a few runs give the filtered one a chance to go first
+	for i := 0; i < 10; i++ {
+		result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName,
+			&search.MatchQuery{Text: "hujan", Fields: []string{"title", "body"}},
+			search.SearchOptions{Size: 10, Aggregations: map[string]search.Aggregation{
+				"sari": &search.TermsAggregation{
+					Field:  search.ArticleFieldAuthor,
+					Size:   10,
+					Filter: &search.TermsQuery{Field: search.ArticleFieldAuthor, Values: []string{"Sari"}},
+				},
+				"authors": &search.TermsAggregation{Field: search.ArticleFieldAuthor, Size: 10},
+				"weeks":   &search.DateHistogramAggregation{Field: search.ArticleFieldCreatedAt, Interval: "week"},
+			}})
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a1", "a2"}, hitIDs(result))
+		assert.Equal(t, []search.Bucket{{Key: "Sari", Count: 1}}, result.Aggregations["sari"])
+		assert.Equal(t, []search.Bucket{{Key: "Bara", Count: 1}, {Key: "Sari", Count: 1}}, result.Aggregations["authors"])
+		assert.Equal(t, []search.Bucket{{Key: "2025-01-06", Count: 2}}, result.Aggregations["weeks"])
+	}
+}
+
+func TestMemorySearch_ArrayFieldsMatchAnyValue(t *testing.T) {
+	svc := memoryArticles(t, append(memoryDocs[:2:2],
+		&search.ArticleDocument{ID: "a4", Title: "Liputan Banjir", Authors: []string{"Sari", "Bara Ali"}, CreatedAt: time.Date(2025, 2, 5, 8, 0, 0, 0, time.UTC)})...)
+
+	result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName,
+		&search.TermsQuery{Field: search.ArticleFieldAuthor, Values: []string{"Bara Ali"}},
+		search.SearchOptions{Size: 10, Aggregations: map[string]search.Aggregation{
+			"authors": &search.TermsAggregation{Field: search.ArticleFieldAuthor, Size: 10},
+		}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a4"}, hitIDs(result))
+	assert.Equal(t, []search.Bucket{{Key: "Bara Ali", Count: 1}, {Key: "Sari", Count: 1}}, result.Aggregations["authors"])
+
+	// A phrase matches within a value but not across two of them
+	for phrase, ids := range map[string][]string{"bara ali": {"a4"}, "sari bara": {}} {
+		result, err = svc.SearchDocuments(context.Background(), search.ArticleIndexName,
+			&search.MatchQuery{Text: phrase, Fields: []string{search.ArticleFieldAuthor}, Mode: search.MatchPhrase},
+			search.SearchOptions{Size: 10})
+		require.NoError(t, err)
+		assert.Equal(t, ids, hitIDs(result), phrase)
+	}
+}
+
+func TestMemorySearch_PrefixMatchesWordsBeingTyped(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+
+	result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName,
+		&search.MatchQuery{Text: "banjir jak", Fields: []string{search.ArticleFieldTitle}, Mode: search.MatchPrefix},
+		search.SearchOptions{Size: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1"}, hitIDs(result))
+}
+
+func TestMemorySearch_MoreLikeThis(t *testing.T) {
+	svc := memoryArticles(t, memoryDocs...)
+
+	query := &search.BoolQuery{
+		Must: []search.Query{&search.MoreLikeThisQuery{
+			Fields:      []string{search.ArticleFieldTitle, search.ArticleFieldBody},
+			LikeText:    "Banjir Jakarta\n\nCurah hujan tinggi membuat banjir di Jakarta.",
+			MinTermFreq: 1,
+		}},
+		MustNot: []search.Query{&search.IDsQuery{IDs: []string{"a1"}}},
+	}
+	result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName, query, search.SearchOptions{Size: 10})
+
+	require.NoError(t, err)
+	assert.NotContains(t, hitIDs(result), "a1")
+	assert.NotEmpty(t, result.Hits)
+}
+
+func TestMemorySearch_PercolatesStoredQueries(t *testing.T) {
+	ctx := context.Background()
+	svc := search.NewMemorySearchService()
+	require.NoError(t, svc.CreateIndex(ctx, search.SavedSearchIndexName, search.SavedSearchMapping))
+	require.NoError(t, svc.BulkIndexDocuments(ctx, search.SavedSearchIndexName, map[string]interface{}{
+		"banjir": &search.SavedSearchDocument{Query: &search.MatchQuery{Text: "banjir", Fields: []string{"title", "body"}}},
+		"sari": &search.SavedSearchDocument{Query: &search.BoolQuery{
+			Filter: []search.Query{&search.TermsQuery{Field: search.ArticleFieldAuthor, Values: []string{"Sari"}}},
+		}},
+	}))
+
+	ids, err := svc.Percolate(ctx, search.SavedSearchIndexName, memoryDocs[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"banjir"}, ids)
+
+	require.NoError(t, svc.DeleteDocument(ctx, search.SavedSearchIndexName, "banjir"))
+	ids, err = svc.Percolate(ctx, search.SavedSearchIndexName, memoryDocs[0])
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestMemorySearch_UpdatesDeletesAndSwitchesAliases(t *testing.T) {
+	ctx := context.Background()
+	svc := memoryArticles(t, memoryDocs...)
+
+	require.NoError(t, svc.UpdateDocument(ctx, search.ArticleIndexName, "a2", map[string]interface{}{"title": "Banjir di Bogor"}))
+	require.NoError(t, svc.DeleteDocument(ctx, search.ArticleIndexName, "a1"))
+	result, err := svc.SearchDocuments(ctx, search.ArticleIndexName,
+		&search.MatchQuery{Text: "banjir", Fields: []string{"title"}}, search.SearchOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a2"}, hitIDs(result))
+
+	// Only fields sent in the update change
+	var doc search.ArticleDocument
+	require.NoError(t, json.Unmarshal(result.Hits[0].Source, &doc))
+	assert.Equal(t, []string{"Sari"}, doc.Authors)
+
+	require.NoError(t, svc.CreateIndex(ctx, "articles_v5", search.ArticleMapping))
+	previous, err := svc.SwitchAlias(ctx, search.ArticleIndexName, "articles_v5")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"articles_v4"}, previous)
+	result, err = svc.SearchDocuments(ctx, search.ArticleIndexName, &search.MatchAllQuery{}, search.SearchOptions{Size: 10})
+	require.NoError(t, err)
+	assert.Zero(t, result.Total)
+
+	_, err = svc.SearchDocuments(ctx, "missing", &search.MatchAllQuery{}, search.SearchOptions{})
+	assert.Error(t, err)
+}
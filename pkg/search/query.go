@@ -0,0 +1,130 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query selects and scores documents. It is built from the query types of this package,
+// which every SearchService implementation understands.
+type Query interface {
+	isQuery()
+}
+
+// MatchAllQuery matches every document with the same score.
+type MatchAllQuery struct{}
+
+// BoolQuery combines queries. A document matches when it matches every Must and Filter query
+// and no MustNot query. Should queries add to the score; when there is no Must or Filter query,
+// at least one of them has to match. Filter queries do not affect the score.
+type BoolQuery struct {
+	Must    []Query
+	Should  []Query
+	MustNot []Query
+	Filter  []Query
+}
+
+// MatchMode tells how the text of a MatchQuery is matched.
+type MatchMode int
+
+const (
+	// MatchWords matches documents containing any of the words, the more the better
+	MatchWords MatchMode = iota
+	// MatchPhrase matches documents containing the words next to each other, in order
+	MatchPhrase
+	// MatchPrefix matches words as they are being typed: the last word may be incomplete.
+	// Only fields with suggestions (title and author) support it.
+	MatchPrefix
+)
+
+// MatchQuery matches analyzed text in any of Fields. A field may carry a boost weighing its
+// matches, written as field^boost (e.g. "title^3").
+type MatchQuery struct {
+	Text   string
+	Fields []string
+	Mode   MatchMode
+	Fuzzy  bool // Tolerate typos in MatchWords mode, more for longer words
+}
+
+// TermsQuery matches documents whose keyword Field is exactly one of Values.
+type TermsQuery struct {
+	Field  string
+	Values []string
+	Boost  float64 // Score of a match, 1 when zero
+}
+
+// DateRangeQuery matches documents whose date Field is in [From, To). A zero bound is open.
+type DateRangeQuery struct {
+	Field string
+	From  time.Time
+	To    time.Time
+}
+
+// IDsQuery matches the documents with the given IDs.
+type IDsQuery struct {
+	IDs []string
+}
+
+// MoreLikeThisQuery matches documents whose Fields share the most significant terms of LikeText.
+type MoreLikeThisQuery struct {
+	Fields        []string
+	LikeText      string
+	MinTermFreq   int // Terms occurring fewer times in LikeText are ignored
+	MaxQueryTerms int // Most significant terms of LikeText that are looked for
+}
+
+func (*MatchAllQuery) isQuery()     {}
+func (*BoolQuery) isQuery()         {}
+func (*MatchQuery) isQuery()        {}
+func (*TermsQuery) isQuery()        {}
+func (*DateRangeQuery) isQuery()    {}
+func (*IDsQuery) isQuery()          {}
+func (*MoreLikeThisQuery) isQuery() {}
+
+// Aggregation summarises the documents matching a search into buckets.
+type Aggregation interface {
+	isAggregation()
+}
+
+// TermsAggregation counts documents by the value of a keyword field, returning the Size most common values.
+// Filter only counts the documents it matches. Global counts over every document of the index
+// instead of those matching the search.
+type TermsAggregation struct {
+	Field  string
+	Size   int
+	Filter Query
+	Global bool
+}
+
+// DateHistogramAggregation counts documents per calendar interval of a date field, skipping empty
+// intervals. Bucket keys are the first day of each interval, formatted as YYYY-MM-DD.
+type DateHistogramAggregation struct {
+	Field    string
+	Interval string // day, week (starting on Monday) or month
+}
+
+func (*TermsAggregation) isAggregation()         {}
+func (*DateHistogramAggregation) isAggregation() {}
+
+// Bucket is a value of an aggregation with the number of documents it counts.
+type Bucket struct {
+	Key   string
+	Count int64
+}
+
+// ScoreField sorts search results by relevance.
+const ScoreField = "_score"
+
+// parseFieldBoost splits a field written as field^boost, returning a boost of 1 when there is none.
+func parseFieldBoost(field string) (string, float64) {
+	name, boost, found := strings.Cut(field, "^")
+	if !found {
+		return field, 1
+	}
+	value, err := strconv.ParseFloat(boost, 64)
+	if err != nil {
+		return name, 1
+	}
+	return name, value
+}
@@ -2,24 +2,92 @@ package search
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/olivere/elastic/v7"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	ArticleIndexName = "articles"
+	ArticleIndexName     = "articles"
+	SavedSearchIndexName = "saved_searches"
 )
 
-// SearchService defines the interface for generic Elasticsearch operations.
-// It exposes methods for indexing and performing general searches.
+// SearchService defines the interface of a search backend, see NewSearchService for Elasticsearch
+// and NewMemorySearchService for an in-process one.
+// It exposes methods for indexing, performing general searches and matching stored queries.
 type SearchService interface {
 	IndexDocument(ctx context.Context, indexName string, id string, doc interface{}) error
-	SearchDocuments(ctx context.Context, indexName string, query elastic.Query, from, size int, sort_asc bool, by string) (*elastic.SearchResult, error)
+	UpdateDocument(ctx context.Context, indexName string, id string, doc interface{}) error
+	DeleteDocument(ctx context.Context, indexName string, id string) error
+	SearchDocuments(ctx context.Context, indexName string, query Query, opts SearchOptions) (*SearchResult, error)
+	Percolate(ctx context.Context, indexName string, doc interface{}) ([]string, error)
+	BulkIndexDocuments(ctx context.Context, indexName string, docs map[string]interface{}) error
+	IndexExists(ctx context.Context, indexName string) (bool, error)
+	CreateIndex(ctx context.Context, indexName string, body string) error
+	DeleteIndex(ctx context.Context, indexName string) error
+	SwitchAlias(ctx context.Context, alias string, indexName string) ([]string, error)
 	Close()
 }
 
+// SearchOptions controls paging and ordering of a search.
+// When SearchAfter is set, From is ignored and results continue after that sort position.
+// TrackScores computes hit scores even when results are not sorted by ScoreField.
+// Source limits the returned document fields; all fields are returned when empty.
+// Timeout bounds the time spent searching, returning the hits found so far when it runs out.
+type SearchOptions struct {
+	From        int
+	Size        int
+	Sort        []SortField
+	SearchAfter []interface{}
+	TrackScores bool
+	Source      []string
+	Timeout     time.Duration
+	Highlight   *HighlightOptions
+	// Aggregations are computed over every matching document, keyed by the name used to read them back
+	Aggregations map[string]Aggregation
+}
+
+// HighlightOptions requests highlighted fragments of the matching text in each hit.
+// The document text is HTML-escaped, so only the tags are markup.
+type HighlightOptions struct {
+	Fields       []string
+	FragmentSize int // Characters per fragment
+	Fragments    int // Maximum fragments per field
+	PreTag       string
+	PostTag      string
+}
+
+// SortField orders search results by a single field, or by relevance with ScoreField.
+type SortField struct {
+	Field     string
+	Ascending bool
+}
+
+// SearchResult is a page of search hits, with the total number of matching documents
+// and the buckets of each requested aggregation.
+type SearchResult struct {
+	Total        int64
+	Hits         []*Hit
+	Aggregations map[string][]Bucket
+}
+
+// Hit is a single matching document.
+// Sort holds its position for SearchOptions.SearchAfter when the search was sorted.
+type Hit struct {
+	ID        string
+	Score     *float64
+	Sort      []interface{}
+	Source    json.RawMessage
+	Highlight map[string][]string
+}
+
+// maxPercolateMatches bounds the stored queries a percolated document can match,
+// the most a search returns by default.
+const maxPercolateMatches = 10000
+
 type elasticSearchService struct {
 	client *elastic.Client
 }
@@ -30,10 +98,14 @@ func NewSearchService(esClient *elastic.Client) SearchService {
 
 // IndexDocument adds or updates a document in a specified index.
 func (s *elasticSearchService) IndexDocument(ctx context.Context, indexName string, id string, doc interface{}) error {
-	_, err := s.client.Index().
+	body, err := elasticDocument(doc)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Index().
 		Index(indexName).
 		Id(id).
-		BodyJson(doc).
+		BodyJson(body).
 		Do(ctx)
 	if err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
@@ -46,29 +118,211 @@ func (s *elasticSearchService) IndexDocument(ctx context.Context, indexName stri
 	return nil
 }
 
-// SearchDocuments performs a search using a provided Elasticsearch query.
-// It returns the raw search result which can then be processed by the caller.
-func (s *elasticSearchService) SearchDocuments(ctx context.Context, indexName string, query elastic.Query, from, size int, sort_asc bool, by string) (*elastic.SearchResult, error) {
+// UpdateDocument merges the given fields into an existing document.
+// The document is created if it does not exist yet, so a previously failed index is repaired.
+func (s *elasticSearchService) UpdateDocument(ctx context.Context, indexName string, id string, doc interface{}) error {
+	_, err := s.client.Update().
+		Index(indexName).
+		Id(id).
+		Doc(doc).
+		DocAsUpsert(true).
+		Do(ctx)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"index": indexName,
+			"id":    id,
+		}).Error("Failed to update document in Elasticsearch")
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+	logrus.WithFields(logrus.Fields{"index": indexName, "id": id}).Info("Document updated in Elasticsearch")
+	return nil
+}
+
+// DeleteDocument removes a document from a specified index.
+// Deleting a document that is not indexed is not treated as an error.
+func (s *elasticSearchService) DeleteDocument(ctx context.Context, indexName string, id string) error {
+	_, err := s.client.Delete().
+		Index(indexName).
+		Id(id).
+		Do(ctx)
+	if err != nil && !elastic.IsNotFound(err) {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"index": indexName,
+			"id":    id,
+		}).Error("Failed to delete document from Elasticsearch")
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	logrus.WithFields(logrus.Fields{"index": indexName, "id": id}).Info("Document deleted from Elasticsearch")
+	return nil
+}
+
+// SearchDocuments performs a search, translating the query into the Elasticsearch query DSL.
+func (s *elasticSearchService) SearchDocuments(ctx context.Context, indexName string, query Query, opts SearchOptions) (*SearchResult, error) {
 	searchService := s.client.Search().
 		Index(indexName).
-		Query(query).
-		From(from).
-		Size(size)
+		Query(elasticQuery(query)).
+		Size(opts.Size).
+		TrackTotalHits(true).
+		TrackScores(opts.TrackScores)
+
+	for _, sort := range opts.Sort {
+		searchService.Sort(sort.Field, sort.Ascending)
+	}
+
+	if opts.Timeout > 0 {
+		searchService.Timeout(fmt.Sprintf("%dms", opts.Timeout.Milliseconds()))
+	}
+
+	if len(opts.Source) > 0 {
+		searchService.FetchSourceContext(elastic.NewFetchSourceContext(true).Include(opts.Source...))
+	}
+
+	for name, agg := range opts.Aggregations {
+		searchService.Aggregation(name, elasticAggregation(agg))
+	}
+
+	if opts.Highlight != nil {
+		highlight := elastic.NewHighlight().
+			Encoder("html").
+			FragmentSize(opts.Highlight.FragmentSize).
+			NumOfFragments(opts.Highlight.Fragments).
+			PreTags(opts.Highlight.PreTag).
+			PostTags(opts.Highlight.PostTag)
+		for _, field := range opts.Highlight.Fields {
+			highlight.Fields(elastic.NewHighlighterField(field))
+		}
+		searchService.Highlight(highlight)
+	}
 
-	if by != "" {
-		searchService.Sort(by, sort_asc)
+	if len(opts.SearchAfter) > 0 {
+		searchService.SearchAfter(opts.SearchAfter...)
+	} else {
+		searchService.From(opts.From)
 	}
 
 	searchResult, err := searchService.Do(ctx)
 	if err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"index": indexName,
-			"from":  from,
-			"size":  size,
+			"from":  opts.From,
+			"size":  opts.Size,
 		}).Error("Elasticsearch search failed")
 		return nil, fmt.Errorf("failed to perform search: %w", err)
 	}
-	return searchResult, nil
+
+	result := &SearchResult{Hits: []*Hit{}, Aggregations: map[string][]Bucket{}}
+	if searchResult.Hits != nil {
+		if searchResult.Hits.TotalHits != nil {
+			result.Total = searchResult.Hits.TotalHits.Value
+		}
+		for _, hit := range searchResult.Hits.Hits {
+			result.Hits = append(result.Hits, &Hit{
+				ID:        hit.Id,
+				Score:     hit.Score,
+				Sort:      hit.Sort,
+				Source:    hit.Source,
+				Highlight: hit.Highlight,
+			})
+		}
+	}
+	for name, agg := range opts.Aggregations {
+		result.Aggregations[name] = readAggregation(searchResult.Aggregations, name, agg)
+	}
+	return result, nil
+}
+
+// Percolate returns the IDs of the stored queries in a percolator index that match doc.
+func (s *elasticSearchService) Percolate(ctx context.Context, indexName string, doc interface{}) ([]string, error) {
+	searchResult, err := s.client.Search().
+		Index(indexName).
+		Query(elastic.NewPercolatorQuery().Field(SavedSearchFieldQuery).Document(doc)).
+		Size(maxPercolateMatches).
+		FetchSource(false).
+		Do(ctx)
+	if err != nil {
+		logrus.WithError(err).WithField("index", indexName).Error("Elasticsearch percolation failed")
+		return nil, fmt.Errorf("failed to percolate document: %w", err)
+	}
+
+	ids := []string{}
+	if searchResult.Hits != nil {
+		for _, hit := range searchResult.Hits.Hits {
+			ids = append(ids, hit.Id)
+		}
+	}
+	return ids, nil
+}
+
+// BulkIndexDocuments adds or replaces many documents, keyed by ID, in a single bulk request.
+func (s *elasticSearchService) BulkIndexDocuments(ctx context.Context, indexName string, docs map[string]interface{}) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	bulk := s.client.Bulk().Index(indexName)
+	for id, doc := range docs {
+		body, err := elasticDocument(doc)
+		if err != nil {
+			return err
+		}
+		bulk.Add(elastic.NewBulkIndexRequest().Id(id).Doc(body))
+	}
+
+	res, err := bulk.Do(ctx)
+	if err != nil {
+		logrus.WithError(err).WithField("index", indexName).Error("Elasticsearch bulk request failed")
+		return fmt.Errorf("failed to bulk index documents: %w", err)
+	}
+	if failed := res.Failed(); len(failed) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"index":  indexName,
+			"failed": len(failed),
+			"reason": failed[0].Error,
+		}).Error("Elasticsearch rejected documents in bulk request")
+		return fmt.Errorf("failed to bulk index %d of %d documents", len(failed), len(docs))
+	}
+	return nil
+}
+
+// IndexExists reports whether an index or alias with the given name exists.
+func (s *elasticSearchService) IndexExists(ctx context.Context, indexName string) (bool, error) {
+	exists, err := s.client.IndexExists(indexName).Do(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check index existence: %w", err)
+	}
+	return exists, nil
+}
+
+// CreateIndex creates an index with the given settings and mapping.
+func (s *elasticSearchService) CreateIndex(ctx context.Context, indexName string, body string) error {
+	if err := createIndex(ctx, s.client, indexName, body); err != nil {
+		return err
+	}
+	logrus.WithField("index", indexName).Info("Elasticsearch index created")
+	return nil
+}
+
+// DeleteIndex removes an index and all of its documents.
+func (s *elasticSearchService) DeleteIndex(ctx context.Context, indexName string) error {
+	if _, err := s.client.DeleteIndex(indexName).Do(ctx); err != nil {
+		return fmt.Errorf("failed to delete index: %w", err)
+	}
+	logrus.WithField("index", indexName).Info("Elasticsearch index deleted")
+	return nil
+}
+
+// SwitchAlias refreshes the given index and atomically points the alias at it.
+// It returns the indices the alias pointed at before, which are kept.
+func (s *elasticSearchService) SwitchAlias(ctx context.Context, alias string, indexName string) ([]string, error) {
+	if _, err := s.client.Refresh(indexName).Do(ctx); err != nil {
+		return nil, fmt.Errorf("failed to refresh index: %w", err)
+	}
+	previous, err := switchAlias(ctx, s.client, alias, indexName)
+	if err != nil {
+		return nil, err
+	}
+	logrus.WithFields(logrus.Fields{"alias": alias, "index": indexName}).Info("Elasticsearch alias switched")
+	return previous, nil
 }
 
 // Close closes the underlying Elasticsearch client connection (if needed).
@@ -0,0 +1,90 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kumparan-test/pkg/search"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeElasticsearch answers every request with response and records the body of the last one.
+func fakeElasticsearch(t *testing.T, response string) (search.SearchService, *map[string]interface{}) {
+	t.Helper()
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		body = nil
+		_ = json.Unmarshal(raw, &body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := elastic.NewClient(elastic.SetURL(server.URL), elastic.SetSniff(false), elastic.SetHealthcheck(false))
+	require.NoError(t, err)
+	return search.NewSearchService(client), &body
+}
+
+func TestElasticSearch_TranslatesQueryAndReadsResult(t *testing.T) {
+	svc, body := fakeElasticsearch(t, `{
+		"hits": {"total": {"value": 1}, "hits": [{"_id": "a1", "_score": 1.5, "_source": {"id": "a1"}, "highlight": {"title": ["<em>Banjir</em>"]}}]},
+		"aggregations": {"authors": {"doc_count": 9, "filter": {"doc_count": 3, "buckets": [{"key": "Bara", "doc_count": 3}]}}}
+	}`)
+
+	result, err := svc.SearchDocuments(context.Background(), search.ArticleIndexName,
+		&search.BoolQuery{
+			Must:   []search.Query{&search.MatchQuery{Text: "banjr", Fields: []string{"title^3", "body"}, Fuzzy: true}},
+			Filter: []search.Query{&search.TermsQuery{Field: "author", Values: []string{"Bara"}}},
+		},
+		search.SearchOptions{Size: 10, Aggregations: map[string]search.Aggregation{
+			"authors": &search.TermsAggregation{
+				Field:  "author",
+				Size:   5,
+				Filter: &search.MatchQuery{Text: "ba", Fields: []string{"author"}, Mode: search.MatchPrefix},
+			},
+		}})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, "a1", result.Hits[0].ID)
+	assert.Equal(t, 1.5, *result.Hits[0].Score)
+	assert.Equal(t, []string{"<em>Banjir</em>"}, result.Hits[0].Highlight["title"])
+	assert.Equal(t, []search.Bucket{{Key: "Bara", Count: 3}}, result.Aggregations["authors"])
+
+	request := *body
+	assert.Equal(t, map[string]interface{}{"bool": map[string]interface{}{
+		"must": map[string]interface{}{"multi_match": map[string]interface{}{
+			"query": "banjr", "fields": []interface{}{"title^3", "body"}, "fuzziness": "AUTO",
+		}},
+		"filter": map[string]interface{}{"terms": map[string]interface{}{"author": []interface{}{"Bara"}}},
+	}}, request["query"])
+
+	// Prefixes are matched on the search-as-you-type subfields
+	filter := request["aggregations"].(map[string]interface{})["authors"].(map[string]interface{})["filter"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"multi_match": map[string]interface{}{
+		"query":  "ba",
+		"fields": []interface{}{"author.suggest", "author.suggest._2gram", "author.suggest._3gram"},
+		"type":   "bool_prefix",
+	}}, filter)
+}
+
+func TestElasticSearch_PercolateReturnsMatchingIDs(t *testing.T) {
+	svc, body := fakeElasticsearch(t, `{"hits": {"total": {"value": 2}, "hits": [{"_id": "search-1"}, {"_id": "search-2"}]}}`)
+
+	ids, err := svc.Percolate(context.Background(), search.SavedSearchIndexName, &search.ArticleDocument{ID: "a1", Title: "Banjir"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"search-1", "search-2"}, ids)
+	percolate := (*body)["query"].(map[string]interface{})["percolate"].(map[string]interface{})
+	assert.Equal(t, search.SavedSearchFieldQuery, percolate["field"])
+	assert.Equal(t, "Banjir", percolate["document"].(map[string]interface{})["title"])
+}